@@ -45,6 +45,55 @@ func BenchmarkSetAnd(b *testing.B) {
 	}
 }
 
+// Number of words in large test set.
+const nwLarge = 1 << 20
+
+func BenchmarkSizeLarge(b *testing.B) {
+	s := BuildTestSet(nwLarge << 3)
+	b.ResetTimer()
+	for i := 0; i < b.N/nwLarge; i++ { // Measure time per word.
+		s.Size()
+	}
+}
+
+func BenchmarkParallelSize(b *testing.B) {
+	s := BuildTestSet(nwLarge << 3)
+	b.ResetTimer()
+	for i := 0; i < b.N/nwLarge; i++ { // Measure time per word.
+		s.ParallelSize()
+	}
+}
+
+func BenchmarkSetAndLarge(b *testing.B) {
+	s := New(64*nwLarge - 1).Delete(64*nwLarge - 1) // Allocates nwLarge words.
+	s1 := BuildTestSet(nwLarge << 3)
+	s2 := BuildTestSet(nwLarge << 3)
+	b.ResetTimer()
+	for i := 0; i < b.N/nwLarge; i++ { // Measure time per word.
+		s.SetAnd(s1, s2)
+	}
+}
+
+func BenchmarkParallelSetAnd(b *testing.B) {
+	s := New(64*nwLarge - 1).Delete(64*nwLarge - 1) // Allocates nwLarge words.
+	s1 := BuildTestSet(nwLarge << 3)
+	s2 := BuildTestSet(nwLarge << 3)
+	b.ResetTimer()
+	for i := 0; i < b.N/nwLarge; i++ { // Measure time per word.
+		s.ParallelSetAnd(s1, s2)
+	}
+}
+
+func BenchmarkParallelSetOr(b *testing.B) {
+	s := New(64*nwLarge - 1).Delete(64*nwLarge - 1) // Allocates nwLarge words.
+	s1 := BuildTestSet(nwLarge << 3)
+	s2 := BuildTestSet(nwLarge << 3)
+	b.ResetTimer()
+	for i := 0; i < b.N/nwLarge; i++ { // Measure time per word.
+		s.ParallelSetOr(s1, s2)
+	}
+}
+
 func BenchmarkString(b *testing.B) {
 	s := BuildTestSet(b.N) // As Visit is pretty fast, s can be pretty big.
 	b.ResetTimer()
@@ -0,0 +1,101 @@
+package bit
+
+// NextAbsent returns the next integer n, n > m, not in the set,
+// or -1 if there is no such non-negative integer.
+func (s *Set) NextAbsent(m int) int {
+	if m == MaxInt {
+		return -1
+	}
+	n := max(0, m+1)
+	d := s.data
+	i := n >> shift
+	if i >= len(d) {
+		return n
+	}
+	t := uint(n & mask)
+	w := ^d[i] >> t << t // Zero out bits for numbers < n.
+	for w == 0 {
+		i++
+		if i == len(d) {
+			return i << shift
+		}
+		w = ^d[i]
+	}
+	return i<<shift + trailingZeros64(w)
+}
+
+// PrevAbsent returns the previous integer n, 0 ≤ n < m, not in the set,
+// or -1 if there is no such integer.
+func (s *Set) PrevAbsent(m int) int {
+	if m <= 0 {
+		return -1
+	}
+	n := m - 1
+	d := s.data
+	i := n >> shift
+	if i >= len(d) {
+		return n
+	}
+	w := ^d[i] & bitMask(0, n&mask) // Zero out bits for numbers > n.
+	for w == 0 {
+		i--
+		if i < 0 {
+			return -1
+		}
+		w = ^d[i]
+	}
+	return i<<shift + len64(w) - 1
+}
+
+// Flip adds n to s if n is not in s, and otherwise removes n from s.
+// It returns a pointer to the updated set.
+// A negative n will not be added.
+func (s *Set) Flip(n int) *Set {
+	if n < 0 {
+		return s
+	}
+	i := n >> shift
+	if i >= len(s.data) {
+		s.resize(i + 1)
+	}
+	s.data[i] ^= 1 << uint(n&mask)
+	s.trim()
+	return s
+}
+
+// FlipRange flips the membership of all integers from m to n-1
+// and returns a pointer to the updated set.
+// Negative numbers will not be added.
+func (s *Set) FlipRange(m, n int) *Set {
+	if n < 1 || m >= n {
+		return s
+	}
+	m = max(0, m)
+	n--
+	low, high := m>>shift, n>>shift
+	if high >= len(s.data) {
+		s.resize(high + 1)
+	}
+	d := s.data
+	// Range fits in one word.
+	if low == high {
+		d[low] ^= bitMask(m&mask, n&mask)
+		s.trim()
+		return s
+	}
+	// Range spans at least two words.
+	d[low] ^= bitMask(m&mask, bpw-1)
+	for i := low + 1; i < high; i++ {
+		d[i] = ^d[i]
+	}
+	d[high] ^= bitMask(0, n&mask)
+	s.trim()
+	return s
+}
+
+// Complement creates a new set that consists of all integers
+// from 0 to n-1 that do not belong to s.
+func (s *Set) Complement(n int) *Set {
+	res := new(Set).AddRange(0, n)
+	return res.SetAndNot(res, s)
+}
@@ -0,0 +1,94 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestNextPrevAbsent(t *testing.T) {
+	for _, x := range []struct {
+		s     *Set
+		m     int
+		nextN int
+		prevN int
+	}{
+		{New(), MinInt, 0, -1},
+		{New(), -1, 0, -1},
+		{New(), 0, 1, -1},
+		{New(), 1, 2, 0},
+		{New(), MaxInt, -1, MaxInt - 1},
+
+		{New(0), -1, 1, -1},
+		{New(0), 0, 1, -1},
+		{New(0), 1, 2, -1},
+		{New(0), 2, 3, 1},
+
+		{New(1, 2, 4), 0, 3, -1},
+		{New(1, 2, 4), 1, 3, 0},
+		{New(1, 2, 4), 3, 5, 0},
+		{New(1, 2, 4), 4, 5, 3},
+		{New(1, 2, 4), 5, 6, 3},
+
+		{New().AddRange(0, 64), -1, 64, -1},
+		{New().AddRange(0, 64), 63, 64, -1},
+		{New().AddRange(0, 64), 64, 65, -1},
+		{New().AddRange(0, 64), 65, 66, 64},
+		{New().AddRange(0, 200), 10, 200, -1},
+		{New().AddRange(0, 200), 200, 201, -1},
+		{New().AddRange(0, 200).Delete(70), 10, 70, -1},
+		{New().AddRange(0, 200).Delete(70), 190, 200, 70},
+		{New().AddRange(0, 200).Delete(70), MaxInt, -1, MaxInt - 1},
+		{New().AddRange(60, 130), 100, 130, 59},
+	} {
+		s, m := x.s, x.m
+		if n := s.NextAbsent(m); n != x.nextN {
+			t.Errorf("%v.NextAbsent(%d) = %d; want %d", s, m, n, x.nextN)
+		}
+		if n := s.PrevAbsent(m); n != x.prevN {
+			t.Errorf("%v.PrevAbsent(%d) = %d; want %d", s, m, n, x.prevN)
+		}
+	}
+}
+
+func TestFlip(t *testing.T) {
+	for _, x := range []struct {
+		s   *Set
+		res string
+	}{
+		{New().Flip(-1), "{}"},
+		{New().Flip(1), "{1}"},
+		{New(1).Flip(1), "{}"},
+		{New(1).Flip(2), "{1 2}"},
+		{New(1, 100).Flip(100), "{1}"},
+		{New(1, 100).Flip(65), "{1 65 100}"},
+	} {
+		res := x.s.String()
+		if res != x.res {
+			t.Errorf("s.Flip() = %q; want %q", res, x.res)
+		}
+		CheckInvariants(t, "Flip", x.s)
+	}
+}
+
+func TestComplement(t *testing.T) {
+	for _, x := range []struct {
+		s   *Set
+		n   int
+		res string
+	}{
+		{New(), -1, "{}"},
+		{New(), 0, "{}"},
+		{New(), 3, "{0..2}"},
+		{New(1), 3, "{0 2}"},
+		{New(0, 1, 2), 3, "{}"},
+		{New(1, 100), 3, "{0 2}"},
+		{New(1, 100), 102, "{0 2..99 101}"},
+		{New().AddRange(0, 64), 130, "{64..129}"},
+	} {
+		s := x.s
+		res := s.Complement(x.n)
+		if res.String() != x.res {
+			t.Errorf("%v.Complement(%d) = %v; want %s", s, x.n, res, x.res)
+		}
+		CheckInvariants(t, "Complement", res)
+	}
+}
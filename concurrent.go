@@ -0,0 +1,236 @@
+package bit
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentSet represents a set of non-negative integers that is safe
+// for concurrent use by multiple goroutines. The zero value is an empty
+// set ready to use.
+//
+// The words of a ConcurrentSet are stored in segments that are never
+// moved or freed. Add, Delete and Contains update or read a single word
+// using atomic operations, without locking. Only when an element beyond
+// the allocated segments is added does Add take a lock to grow the set.
+//
+// The methods that read more than one word, such as Size, Next and Visit,
+// don't take a snapshot of the set. If the set is modified concurrently,
+// they report every element that is present during the whole call and
+// no element that is absent during the whole call; an element that is
+// added or deleted during the call may or may not be reported.
+// Use Snapshot to get a consistent copy when there are no concurrent
+// writers.
+type ConcurrentSet struct {
+	mu   sync.Mutex   // held when adding segments
+	segs atomic.Value // []*segment; a longer slice is stored to grow the set
+}
+
+// segWords is the number of words in a segment of a ConcurrentSet.
+const segWords = 1 << 10
+
+type segment [segWords]uint64
+
+// NewConcurrentSet creates a new empty set, with room for the elements
+// from 0 to n-1 preallocated.
+func NewConcurrentSet(n int) *ConcurrentSet {
+	s := new(ConcurrentSet)
+	if n > 0 {
+		s.segment((n - 1) >> shift / segWords)
+	}
+	return s
+}
+
+// load returns the current segments of s.
+func (s *ConcurrentSet) load() []*segment {
+	segs, _ := s.segs.Load().([]*segment)
+	return segs
+}
+
+// segment returns the segment with index i, growing s if necessary.
+func (s *ConcurrentSet) segment(i int) *segment {
+	if segs := s.load(); i < len(segs) {
+		return segs[i]
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	segs := s.load()
+	if i < len(segs) {
+		return segs[i]
+	}
+	// Readers only see the first len(segs) entries of the backing array,
+	// so it's safe for append to reuse it.
+	for len(segs) <= i {
+		segs = append(segs, new(segment))
+	}
+	s.segs.Store(segs)
+	return segs[i]
+}
+
+// segWord returns a pointer to the word with index i in segs, or nil if
+// there is no such word.
+func segWord(segs []*segment, i int) *uint64 {
+	if j := i / segWords; j < len(segs) {
+		return &segs[j][i%segWords]
+	}
+	return nil
+}
+
+// Contains tells if n is an element of the set.
+func (s *ConcurrentSet) Contains(n int) bool {
+	if n < 0 {
+		return false
+	}
+	p := segWord(s.load(), n>>shift)
+	return p != nil && atomic.LoadUint64(p)&(1<<uint(n&mask)) != 0
+}
+
+// Add adds n to s and tells if n was added, that is,
+// if n didn't already belong to the set. A negative n will not be added.
+func (s *ConcurrentSet) Add(n int) (added bool) {
+	if n < 0 {
+		return false
+	}
+	i := n >> shift
+	p := &s.segment(i / segWords)[i%segWords]
+	b := uint64(1) << uint(n&mask)
+	for {
+		w := atomic.LoadUint64(p)
+		if w&b != 0 {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(p, w, w|b) {
+			return true
+		}
+	}
+}
+
+// Delete removes n from s and tells if n was removed, that is,
+// if n belonged to the set.
+func (s *ConcurrentSet) Delete(n int) (deleted bool) {
+	if n < 0 {
+		return false
+	}
+	p := segWord(s.load(), n>>shift)
+	if p == nil {
+		return false
+	}
+	b := uint64(1) << uint(n&mask)
+	for {
+		w := atomic.LoadUint64(p)
+		if w&b == 0 {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(p, w, w&^b) {
+			return true
+		}
+	}
+}
+
+// Size returns the number of elements in the set.
+func (s *ConcurrentSet) Size() int {
+	n := 0
+	for _, seg := range s.load() {
+		for i := range seg {
+			n += onesCount64(atomic.LoadUint64(&seg[i]))
+		}
+	}
+	return n
+}
+
+// Empty tells if the set is empty.
+func (s *ConcurrentSet) Empty() bool {
+	return s.Next(-1) == -1
+}
+
+// Next returns the next element n, n > m, in the set,
+// or -1 if there is no such element.
+func (s *ConcurrentSet) Next(m int) int {
+	if m == MaxInt {
+		return -1
+	}
+	m = max(0, m+1) // Look for smallest element n ≥ m.
+	segs := s.load()
+	end := len(segs) * segWords
+	i := m >> shift
+	if i >= end {
+		return -1
+	}
+	t := uint(m & mask)
+	w := atomic.LoadUint64(segWord(segs, i)) >> t << t // Zero out bits for numbers < m.
+	for w == 0 {
+		i++
+		if i == end {
+			return -1
+		}
+		w = atomic.LoadUint64(segWord(segs, i))
+	}
+	return i<<shift + trailingZeros64(w)
+}
+
+// Prev returns the previous element n, n < m, in the set,
+// or -1 if there is no such element.
+func (s *ConcurrentSet) Prev(m int) int {
+	if m <= 0 {
+		return -1
+	}
+	m-- // Look for largest element n ≤ m.
+	segs := s.load()
+	i := m >> shift
+	var w uint64
+	if end := len(segs) * segWords; i >= end {
+		i = end
+	} else {
+		w = atomic.LoadUint64(segWord(segs, i)) & bitMask(0, m&mask) // Zero out bits for numbers > m.
+	}
+	for w == 0 {
+		i--
+		if i < 0 {
+			return -1
+		}
+		w = atomic.LoadUint64(segWord(segs, i))
+	}
+	return i<<shift + len64(w) - 1
+}
+
+// Visit calls the do function for each element of s in numerical order.
+// If do returns true, Visit returns immediately, skipping any remaining
+// elements, and returns true. It is safe for do to change the set.
+func (s *ConcurrentSet) Visit(do func(n int) (skip bool)) (aborted bool) {
+	for j, seg := range s.load() {
+		for i := range seg {
+			w := atomic.LoadUint64(&seg[i])
+			n := (j*segWords + i) << shift
+			for w != 0 {
+				b := trailingZeros64(w)
+				if do(n + b) {
+					return true
+				}
+				w &= w - 1
+			}
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the set. The elements
+// are listed in ascending order. Runs of at least three consecutive
+// elements from a to b are given as a..b.
+func (s *ConcurrentSet) String() string {
+	return formatSet(s.Visit)
+}
+
+// Snapshot returns a new Set with the elements of s. If s is modified
+// concurrently, the rules given in the ConcurrentSet documentation apply.
+func (s *ConcurrentSet) Snapshot() *Set {
+	segs := s.load()
+	d := make([]uint64, len(segs)*segWords)
+	for j, seg := range segs {
+		for i := range seg {
+			d[j*segWords+i] = atomic.LoadUint64(&seg[i])
+		}
+	}
+	res := &Set{data: d}
+	res.trim()
+	return res
+}
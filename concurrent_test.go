@@ -0,0 +1,112 @@
+package bit
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSet(t *testing.T) {
+	const big = 3*segWords*bpw + 5 // in the fourth segment
+	for _, s := range []*ConcurrentSet{new(ConcurrentSet), NewConcurrentSet(big + 1)} {
+		if !s.Empty() || s.Size() != 0 || s.String() != "{}" {
+			t.Errorf("%v should be empty", s)
+		}
+		if s.Contains(1) || s.Delete(1) || s.Add(-1) {
+			t.Errorf("%v: wrong result for missing element", s)
+		}
+		if !s.Add(1) || s.Add(1) || !s.Add(2) || !s.Add(3) || !s.Add(big) {
+			t.Errorf("Add gives wrong result")
+		}
+		if str := s.String(); str != "{1..3 196613}" {
+			t.Errorf("s.String() = %q; want %q", str, "{1..3 196613}")
+		}
+		if !s.Delete(2) || s.Delete(2) || s.Contains(2) || !s.Contains(big) {
+			t.Errorf("Delete gives wrong result")
+		}
+		if size := s.Size(); size != 3 {
+			t.Errorf("%v.Size() = %d; want 3", s, size)
+		}
+		b := s.Snapshot()
+		if !b.Equal(New(1, 3, big)) {
+			t.Errorf("%v.Snapshot() = %v", s, b)
+		}
+		CheckInvariants(t, "Snapshot", b)
+		for _, m := range []int{MinInt, -1, 0, 1, 2, 3, 4, 100, big - 1, big, big + 1, 1 << 20, MaxInt} {
+			if n, exp := s.Next(m), b.Next(m); n != exp {
+				t.Errorf("%v.Next(%d) = %d; want %d", s, m, n, exp)
+			}
+			if n, exp := s.Prev(m), b.Prev(m); n != exp {
+				t.Errorf("%v.Prev(%d) = %d; want %d", s, m, n, exp)
+			}
+		}
+	}
+}
+
+func TestConcurrentSetParallel(t *testing.T) {
+	const n = 1 << 16
+	s := new(ConcurrentSet)
+	procs := max(4, runtime.GOMAXPROCS(0))
+
+	// Each goroutine adds all numbers; exactly one of them succeeds for each.
+	var wg sync.WaitGroup
+	added := make([]int, procs)
+	for p := 0; p < procs; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				// Go in different orders to provoke growth from several goroutines.
+				k := i
+				if p%2 == 1 {
+					k = n - 1 - i
+				}
+				if s.Add(k) {
+					added[p]++
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+	total := 0
+	for _, a := range added {
+		total += a
+	}
+	if total != n {
+		t.Errorf("%d successful adds; want %d", total, n)
+	}
+	if size := s.Size(); size != n {
+		t.Errorf("Size() = %d; want %d", size, n)
+	}
+
+	// Delete the even numbers while readers scan the set.
+	// The odd numbers are present during the whole scan.
+	for p := 0; p < procs; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			if p%2 == 0 {
+				for k := p; k < n; k += 2 * procs {
+					s.Delete(k)
+				}
+				return
+			}
+			odd := 0
+			s.Visit(func(k int) (skip bool) {
+				if k%2 == 1 {
+					odd++
+				}
+				return
+			})
+			if odd != n/2 {
+				t.Errorf("Visit found %d odd numbers; want %d", odd, n/2)
+			}
+		}(p)
+	}
+	wg.Wait()
+	for k := 0; k < n; k++ {
+		if s.Contains(k) != (k%2 == 1 || k%(2*procs) >= procs) {
+			t.Errorf("Contains(%d) = %t", k, s.Contains(k))
+		}
+	}
+}
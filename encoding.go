@@ -0,0 +1,93 @@
+package bit
+
+import (
+	"encoding/binary"
+)
+
+// binaryVersion is the version of the binary encoding produced by MarshalBinary.
+const binaryVersion = 1
+
+// maxDecodeWords is the largest number of words in a set decoded from
+// an encoding where a few bytes can stand for a huge set, such as EWAH
+// or the ranges accepted by Parse and UnmarshalJSON.
+// It allows elements less than 2^32 and sets of up to 512 MiB.
+const maxDecodeWords = 1 << 26
+
+// A FormatError reports that the input is not a valid encoding of a set.
+type FormatError struct {
+	Format string // name of the encoding, e.g. "binary"
+	Msg    string // description of the problem
+}
+
+func (e *FormatError) Error() string {
+	return "bit: invalid " + e.Format + " encoding: " + e.Msg
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The encoding consists of a version byte, the number of 64-bit words
+// as an unsigned varint, and the words themselves in little-endian order.
+// The encoding does not depend on the byte order of the machine.
+func (s *Set) MarshalBinary() ([]byte, error) {
+	d := s.data
+	buf := make([]byte, 1+binary.MaxVarintLen64+8*len(d))
+	buf[0] = binaryVersion
+	n := 1 + binary.PutUvarint(buf[1:], uint64(len(d)))
+	for _, w := range d {
+		binary.LittleEndian.PutUint64(buf[n:], w)
+		n += 8
+	}
+	return buf[:n], nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It sets s to the set encoded in data, as produced by MarshalBinary.
+// If data is not a valid encoding, it returns a *FormatError
+// and leaves s unchanged.
+func (s *Set) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return binaryError("no data")
+	}
+	if data[0] != binaryVersion {
+		return binaryError("unknown version")
+	}
+	data = data[1:]
+	n, k := binary.Uvarint(data)
+	if k <= 0 {
+		return binaryError("bad word count")
+	}
+	data = data[k:]
+	if n != uint64(len(data))/8 || len(data)%8 != 0 {
+		return binaryError("word count does not match data length")
+	}
+	if n > 0 && binary.LittleEndian.Uint64(data[len(data)-8:]) == 0 {
+		return binaryError("trailing zero word")
+	}
+	s.realloc(int(n))
+	for i := range s.data {
+		s.data[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
+
+func binaryError(msg string) error {
+	return &FormatError{Format: "binary", Msg: msg}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The encoding is the same as the one returned by String.
+func (s *Set) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It accepts the same format as Parse. If text is not a valid
+// encoding, it returns a *SyntaxError and leaves s unchanged.
+func (s *Set) UnmarshalText(text []byte) error {
+	res, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	s.Set(res)
+	return nil
+}
@@ -0,0 +1,84 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	for _, s := range []*Set{
+		New(),
+		New(0),
+		New(1, 2, 3),
+		New(63, 64),
+		New(100, 200, 300),
+		New().AddRange(10, 1000),
+	} {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Errorf("%v.MarshalBinary() returned error %v", s, err)
+			continue
+		}
+		res := New(5, 500)
+		if err := res.UnmarshalBinary(data); err != nil {
+			t.Errorf("UnmarshalBinary(%v) returned error %v", data, err)
+			continue
+		}
+		if !res.Equal(s) {
+			t.Errorf("UnmarshalBinary(%v.MarshalBinary()) = %v; want %v", s, res, s)
+		}
+		CheckInvariants(t, "UnmarshalBinary", res)
+	}
+}
+
+func TestBinaryError(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		{},
+		{2, 0},
+		{1},
+		{1, 0x80},
+		{1, 1},
+		{1, 1, 1, 0, 0, 0, 0, 0, 0},
+		{1, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 0, 0},
+		{1, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 2, 1, 0, 0, 0, 0, 0, 0, 0},
+		{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1},
+	} {
+		s := New(1, 2, 3)
+		err := s.UnmarshalBinary(data)
+		if _, ok := err.(*FormatError); !ok {
+			t.Errorf("UnmarshalBinary(%v) = %v; want *FormatError", data, err)
+		}
+		if !s.Equal(New(1, 2, 3)) {
+			t.Errorf("UnmarshalBinary(%v) changed set to %v", data, s)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	for _, s := range []*Set{
+		New(),
+		New(1, 2, 3),
+		New(0, 2, 3, 5),
+		New(100, 200, 300),
+	} {
+		text, err := s.MarshalText()
+		if err != nil || string(text) != s.String() {
+			t.Errorf("%v.MarshalText() = %q, %v; want %q, nil", s, text, err, s.String())
+		}
+		res := New(5, 500)
+		if err := res.UnmarshalText(text); err != nil || !res.Equal(s) {
+			t.Errorf("UnmarshalText(%q) = %v, %v; want %v, nil", text, res, err, s)
+		}
+		CheckInvariants(t, "UnmarshalText", res)
+	}
+
+	s := New(1, 2, 3)
+	if err := s.UnmarshalText([]byte("{1 x}")); err == nil {
+		t.Errorf("UnmarshalText(%q) returned nil error", "{1 x}")
+	}
+	if !s.Equal(New(1, 2, 3)) {
+		t.Errorf("UnmarshalText with invalid input changed set to %v", s)
+	}
+}
@@ -0,0 +1,355 @@
+package bit
+
+import (
+	"encoding/binary"
+)
+
+// The EWAH (Enhanced Word-Aligned Hybrid) encoding, as described in
+// “Sorting improves word-aligned bitmap indexes”, Lemire, Kaser and
+// Aouiche, 2010, compresses long runs of words that are all zeros or
+// all ones. The encoding consists of a sequence of 64-bit little-endian
+// words. Each marker word is followed by a number of literal words.
+// A marker word holds
+//   - in bit 0, the value of the fill bits,
+//   - in bits 1 to 32, the number of fill words,
+//   - in bits 33 to 63, the number of literal words that follow,
+//
+// and stands for the fill words followed by the literal words.
+const (
+	ewahFillBits = 32
+	ewahMaxFill  = 1<<ewahFillBits - 1
+	ewahMaxLit   = 1<<(bpw-1-ewahFillBits) - 1
+)
+
+// EncodeEWAH returns the EWAH encoding of s.
+func EncodeEWAH(s *Set) []byte {
+	var e ewahWriter
+	d := s.data
+	for i := 0; i < len(d); {
+		w := d[i]
+		if w != 0 && w != maxw {
+			e.addLiteral(w)
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(d) && d[j] == w {
+			j++
+		}
+		e.addFill(w, uint64(j-i))
+		i = j
+	}
+	return e.bytes()
+}
+
+// DecodeEWAH returns the set encoded in data, which should be in the format
+// produced by EncodeEWAH. If data is not a valid encoding, or if the set
+// has an element n ≥ 2^32, which would take more than 512 MiB of memory,
+// it returns a *FormatError. Use DecodeEWAHLimit to allow larger sets.
+func DecodeEWAH(data []byte) (*Set, error) {
+	return decodeEWAH(data, maxDecodeWords)
+}
+
+// DecodeEWAHLimit is like DecodeEWAH, but instead of 2^32 it uses the
+// given limit: if the set has an element n ≥ limit, it returns
+// a *FormatError.
+func DecodeEWAHLimit(data []byte, limit int) (*Set, error) {
+	maxWords := 0
+	if limit > 0 {
+		maxWords = (limit-1)>>shift + 1
+	}
+	s, err := decodeEWAH(data, maxWords)
+	if err == nil && !s.Empty() && s.Max() >= limit {
+		return nil, ewahError("set too large")
+	}
+	return s, err
+}
+
+// decodeEWAH decodes a set of at most maxWords words.
+func decodeEWAH(data []byte, maxWords int) (*Set, error) {
+	n, err := ewahLen(data, uint64(maxWords))
+	if err != nil {
+		return nil, err
+	}
+	d := make([]uint64, n)
+	r, _ := newEWAHReader(data)
+	for i := 0; r.load(); {
+		if r.fill > 0 {
+			if r.fillWord != 0 {
+				for j := i; j < i+int(r.fill); j++ {
+					d[j] = maxw
+				}
+			}
+			i += int(r.fill)
+			r.fill = 0
+			continue
+		}
+		for ; r.lit > 0; r.lit-- {
+			d[i] = r.literal()
+			i++
+		}
+	}
+	return &Set{data: d}, nil
+}
+
+// ewahLen returns the number of words in the set encoded in data.
+// It checks that there are at most maxWords words and that the last word
+// is nonzero.
+func ewahLen(data []byte, maxWords uint64) (n int, err error) {
+	r, err := newEWAHReader(data)
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	var last uint64
+	for r.load() {
+		if r.fill > 0 {
+			total += r.fill
+			last = r.fillWord
+			r.fill = 0
+		} else {
+			total += uint64(r.lit)
+			r.skipLiterals(r.lit - 1)
+			last = r.literal()
+			r.lit = 0
+		}
+		if total > maxWords {
+			return 0, ewahError("set too large")
+		}
+	}
+	switch {
+	case r.err != nil:
+		return 0, r.err
+	case total > 0 && last == 0:
+		return 0, ewahError("trailing zero word")
+	}
+	return int(total), nil
+}
+
+// AndEWAH returns the EWAH encoding of the intersection of the sets
+// encoded in a and b. The result is computed directly from the encodings,
+// without decompressing runs of fill words. If a or b is not a valid
+// encoding, it returns a *FormatError.
+func AndEWAH(a, b []byte) ([]byte, error) {
+	return ewahOp(a, b, false)
+}
+
+// OrEWAH returns the EWAH encoding of the union of the sets
+// encoded in a and b. The result is computed directly from the encodings,
+// without decompressing runs of fill words. If a or b is not a valid
+// encoding, it returns a *FormatError.
+func OrEWAH(a, b []byte) ([]byte, error) {
+	return ewahOp(a, b, true)
+}
+
+// ewahOp computes the union of a and b if or is true,
+// and the intersection otherwise.
+func ewahOp(a, b []byte, or bool) ([]byte, error) {
+	ra, err := newEWAHReader(a)
+	if err != nil {
+		return nil, err
+	}
+	rb, err := newEWAHReader(b)
+	if err != nil {
+		return nil, err
+	}
+	// absorbing is the fill word that determines the result on its own.
+	absorbing := uint64(0)
+	if or {
+		absorbing = maxw
+	}
+	var e ewahWriter
+	for ra.load() && rb.load() {
+		switch {
+		case ra.fill > 0 && rb.fill > 0:
+			n := minFill(ra.fill, rb.fill)
+			w := ra.fillWord & rb.fillWord
+			if or {
+				w = ra.fillWord | rb.fillWord
+			}
+			e.addFill(w, n)
+			ra.fill -= n
+			rb.fill -= n
+		case ra.fill > 0 || rb.fill > 0:
+			if rb.fill > 0 {
+				ra, rb = rb, ra // Make ra the fill.
+			}
+			n := int(minFill(ra.fill, uint64(rb.lit)))
+			if ra.fillWord == absorbing {
+				e.addFill(absorbing, uint64(n))
+				rb.skipLiterals(n)
+			} else {
+				for i := 0; i < n; i++ {
+					e.addWord(rb.literal())
+				}
+			}
+			ra.fill -= uint64(n)
+			rb.lit -= n
+		default:
+			n := min(ra.lit, rb.lit)
+			for i := 0; i < n; i++ {
+				x, y := ra.literal(), rb.literal()
+				w := x & y
+				if or {
+					w = x | y
+				}
+				e.addWord(w)
+			}
+			ra.lit -= n
+			rb.lit -= n
+		}
+	}
+	if or && ra.err == nil && rb.err == nil { // Copy the rest of the longer encoding.
+		r := ra
+		if rb.load() {
+			r = rb
+		}
+		for r.load() {
+			if r.fill > 0 {
+				e.addFill(r.fillWord, r.fill)
+				r.fill = 0
+				continue
+			}
+			for ; r.lit > 0; r.lit-- {
+				e.addWord(r.literal())
+			}
+		}
+	}
+	if ra.err != nil {
+		return nil, ra.err
+	}
+	if rb.err != nil {
+		return nil, rb.err
+	}
+	return e.bytes(), nil
+}
+
+// ewahReader reads an EWAH encoding one marker word at a time.
+type ewahReader struct {
+	data     []byte
+	fill     uint64 // number of remaining fill words of current marker
+	fillWord uint64 // either 0 or maxw
+	lit      int    // number of remaining literal words of current marker
+	err      error
+}
+
+func newEWAHReader(data []byte) (*ewahReader, error) {
+	if len(data)%8 != 0 {
+		return nil, ewahError("length not a multiple of 8")
+	}
+	return &ewahReader{data: data}, nil
+}
+
+// load reads marker words until there are fill or literal words left
+// to read. It returns false at the end of the encoding or on error.
+func (r *ewahReader) load() bool {
+	for r.fill == 0 && r.lit == 0 {
+		if r.err != nil || len(r.data) == 0 {
+			return false
+		}
+		m := binary.LittleEndian.Uint64(r.data)
+		r.data = r.data[8:]
+		r.fillWord = -(m & 1) // Either 0 or maxw.
+		r.fill = m >> 1 & ewahMaxFill
+		r.lit = int(m >> (1 + ewahFillBits)) // at most ewahMaxLit, which fits in an int
+		if r.lit > len(r.data)/8 {
+			r.err = ewahError("literal words missing")
+			return false
+		}
+		if r.lit == 0 && len(r.data) == 0 && r.fillWord == 0 {
+			r.err = ewahError("trailing zero fill")
+			return false
+		}
+	}
+	return true
+}
+
+// literal returns the next literal word.
+// It does not update r.lit.
+func (r *ewahReader) literal() uint64 {
+	w := binary.LittleEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	return w
+}
+
+// skipLiterals skips the next n literal words.
+// It does not update r.lit.
+func (r *ewahReader) skipLiterals(n int) {
+	r.data = r.data[8*n:]
+}
+
+// ewahWriter produces an EWAH encoding.
+type ewahWriter struct {
+	words  []uint64
+	marker int // index of current marker word, if len(words) > 0
+}
+
+// addWord adds the word w.
+func (e *ewahWriter) addWord(w uint64) {
+	if w == 0 || w == maxw {
+		e.addFill(w, 1)
+	} else {
+		e.addLiteral(w)
+	}
+}
+
+// addFill adds n fill words, each equal to w, which is either 0 or maxw.
+func (e *ewahWriter) addFill(w uint64, n uint64) {
+	bit := w & 1
+	for n > 0 {
+		if len(e.words) > 0 {
+			m := e.words[e.marker]
+			fill := m >> 1 & ewahMaxFill
+			if m>>(1+ewahFillBits) == 0 && (fill == 0 || m&1 == bit) && fill < ewahMaxFill {
+				// Extend the fill of the current marker.
+				k := minFill(n, ewahMaxFill-fill)
+				e.words[e.marker] = (fill+k)<<1 | bit
+				n -= k
+				continue
+			}
+		}
+		e.newMarker()
+	}
+}
+
+// addLiteral adds the literal word w.
+func (e *ewahWriter) addLiteral(w uint64) {
+	if len(e.words) == 0 || e.words[e.marker]>>(1+ewahFillBits) == ewahMaxLit {
+		e.newMarker()
+	}
+	e.words[e.marker] += 1 << (1 + ewahFillBits)
+	e.words = append(e.words, w)
+}
+
+// newMarker starts a new marker word.
+func (e *ewahWriter) newMarker() {
+	e.marker = len(e.words)
+	e.words = append(e.words, 0)
+}
+
+// bytes returns the encoding, without trailing zero fill words.
+func (e *ewahWriter) bytes() []byte {
+	words := e.words
+	if len(words) > 0 {
+		if m := words[e.marker]; m>>(1+ewahFillBits) == 0 && m&1 == 0 {
+			words = words[:e.marker]
+		}
+	}
+	buf := make([]byte, 8*len(words))
+	for i, w := range words {
+		binary.LittleEndian.PutUint64(buf[8*i:], w)
+	}
+	return buf
+}
+
+// minFill returns the smaller of the fill counts a and b.
+func minFill(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func ewahError(msg string) error {
+	return &FormatError{Format: "EWAH", Msg: msg}
+}
@@ -0,0 +1,134 @@
+package bit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func ewahTestSets() []*Set {
+	return []*Set{
+		New(),
+		New(0),
+		New(1, 2, 3),
+		New(100, 200, 300),
+		New().AddRange(0, 64),
+		New().AddRange(0, 1000),
+		New().AddRange(10, 1000).Add(5000),
+		New().AddRange(64, 640).AddRange(6400, 64000),
+		New(1<<16).AddRange(300, 1<<12),
+		BuildTestSet(1<<8).AddRange(1<<12, 1<<13),
+	}
+}
+
+func TestEWAH(t *testing.T) {
+	for _, x := range []struct {
+		s     *Set
+		words int
+	}{
+		{New(), 0},
+		{New(1), 2},
+		{New(64 * 10), 2},
+		{New().AddRange(0, 64*10), 1},
+		{New().AddRange(0, 64*10).Add(64*20 + 1), 3},
+	} {
+		if n := len(EncodeEWAH(x.s)) / 8; n != x.words {
+			t.Errorf("len(EncodeEWAH(%v)) = %d words; want %d", x.s, n, x.words)
+		}
+	}
+
+	for _, s := range ewahTestSets() {
+		res, err := DecodeEWAH(EncodeEWAH(s))
+		if err != nil || !res.Equal(s) {
+			t.Errorf("DecodeEWAH(EncodeEWAH(%v)) = %v, %v; want %v, nil", s, res, err, s)
+			continue
+		}
+		CheckInvariants(t, "DecodeEWAH", res)
+	}
+}
+
+func TestEWAHOp(t *testing.T) {
+	sets := ewahTestSets()
+	for _, a := range sets {
+		for _, b := range sets {
+			ea, eb := EncodeEWAH(a), EncodeEWAH(b)
+			for _, x := range []struct {
+				f    func(a, b []byte) ([]byte, error)
+				exp  *Set
+				name string
+			}{
+				{AndEWAH, a.And(b), "AndEWAH"},
+				{OrEWAH, a.Or(b), "OrEWAH"},
+			} {
+				e, err := x.f(ea, eb)
+				if err != nil {
+					t.Errorf("%s(%v, %v) returned error %v", x.name, a, b, err)
+					continue
+				}
+				if exp := EncodeEWAH(x.exp); string(e) != string(exp) {
+					t.Errorf("%s(%v, %v) = %v; want %v", x.name, a, b, e, exp)
+				}
+			}
+		}
+	}
+}
+
+func TestEWAHError(t *testing.T) {
+	valid := EncodeEWAH(New(1, 2, 3))
+	for _, data := range [][]byte{
+		{1},
+		{0, 0, 0, 0, 4, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0},
+		{0xfe, 0xff, 0xff, 0xff, 1, 0, 0, 0},             // 2^32-1 zero words
+		{3, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}, // one word, then zero fill
+		{0, 0, 0, 0, 0, 0, 0, 0},                         // empty zero fill
+	} {
+		if _, err := DecodeEWAH(data); err == nil {
+			t.Errorf("DecodeEWAH(%v) returned nil error", data)
+		}
+		if _, err := AndEWAH(valid, data); err == nil {
+			t.Errorf("AndEWAH(%v, %v) returned nil error", valid, data)
+		}
+		if _, err := OrEWAH(data, valid); err == nil {
+			t.Errorf("OrEWAH(%v, %v) returned nil error", data, valid)
+		}
+	}
+
+	// Valid encodings of sets too large to decode.
+	fill := []byte{0xff, 0xff, 0xff, 0xff, 1, 0, 0, 0} // 2^32-1 words of ones
+	for _, data := range [][]byte{
+		fill,
+		bytes.Repeat(fill, 9000),
+		{0, 0, 0, 8, 2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}, // 2^26 zero words, then 1
+	} {
+		if _, err := DecodeEWAH(data); err == nil {
+			t.Errorf("DecodeEWAH(%v) returned nil error", data[:8])
+		}
+	}
+}
+
+func TestEWAHLimit(t *testing.T) {
+	data := EncodeEWAH(New(100, 200))
+	for _, x := range []struct {
+		limit int
+		ok    bool
+	}{
+		{0, false},
+		{200, false},
+		{201, true},
+		{MaxInt, true},
+	} {
+		if _, err := DecodeEWAHLimit(data, x.limit); (err == nil) != x.ok {
+			t.Errorf("DecodeEWAHLimit({100 200}, %d) returned error %v", x.limit, err)
+		}
+	}
+	if _, err := DecodeEWAHLimit(EncodeEWAH(New()), 0); err != nil {
+		t.Errorf("DecodeEWAHLimit({}, 0) returned error %v", err)
+	}
+}
+
+func TestEWAHTrailingZero(t *testing.T) {
+	// A literal zero word at the end is never produced by EncodeEWAH.
+	data := []byte{0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if _, err := DecodeEWAH(data); err == nil {
+		t.Errorf("DecodeEWAH(%v) returned nil error", data)
+	}
+}
@@ -72,11 +72,27 @@ func ExampleSet_Visit_abort() {
 	// Output: 2 3 5 7
 }
 
+func ExampleAtLeast() {
+	a, b, c := bit.New(1, 2, 3), bit.New(2, 3, 4), bit.New(3, 4, 5)
+	fmt.Println(bit.AtLeast(2, a, b, c))
+	// Output: {2..4}
+}
+
 func ExampleNew() {
 	fmt.Println(bit.New(0, 1, 10, 10, -1))
 	// Output: {0 1 10}
 }
 
+func ExampleParse() {
+	s, err := bit.Parse("{1..3 5 8}")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(s.Size(), s.Contains(2), s.Contains(4))
+	// Output: 5 true false
+}
+
 func ExampleSet_String() {
 	fmt.Println(bit.New(1, 2, 6, 5, 3))
 	// Output: {1..3 5 6}
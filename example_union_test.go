@@ -5,26 +5,19 @@ import (
 	"github.com/yourbasic/bit"
 )
 
-// Union returns the union of the given sets.
-func Union(s ...*bit.Set) *bit.Set {
-	// Optimization: allocate initital set with adequate capacity.
-	max := -1
-	for _, x := range s {
-		if x.Size() > 0 && x.Max() > max { // Max is not defined for the empty set.
-			max = x.Max()
-		}
+// Combine any number of sets with Union, Intersection and AtLeast.
+func ExampleUnion() {
+	// Users active on each day of the week.
+	days := []*bit.Set{
+		bit.New(1, 2, 5),
+		bit.New(2, 3, 5),
+		bit.New(2, 3, 8),
 	}
-	res := bit.New(max) // A negative number will not be included in the set.
-
-	for _, x := range s {
-		res.SetOr(res, x) // Reuses memory.
-	}
-	return res
-}
-
-// Implement an efficient variadic Union function using SetOr.
-func Example_union() {
-	a, b, c := bit.New(1, 2), bit.New(2, 3), bit.New(5)
-	fmt.Println(Union(a, b, c))
-	// Output: {1..3 5}
+	fmt.Println("some day:", bit.Union(days...))
+	fmt.Println("every day:", bit.Intersection(days...))
+	fmt.Println("at least two days:", bit.AtLeast(2, days...))
+	// Output:
+	// some day: {1..3 5 8}
+	// every day: {2}
+	// at least two days: {2 3 5}
 }
@@ -0,0 +1,958 @@
+package bit
+
+// HybridSet represents a mutable set of non-negative integers.
+// The zero value is an empty set ready to use.
+//
+// A HybridSet is partitioned into chunks of 2^16 possible elements.
+// Each nonempty chunk is stored in a container that is either a sorted
+// array of at most 4096 elements, a bitmap, or a list of runs of
+// consecutive elements. Range and set operations pick whichever is most
+// compact. Add and Delete update a container in place; they look for
+// a more compact type when a run list changes, when an array outgrows
+// its limit or a bitmap shrinks to it, and after every 4096 elements
+// added to a bitmap. This makes a HybridSet suitable for sparse
+// sets with large elements, where a Set would waste memory.
+// The methods of HybridSet have the same names and meaning as those of Set.
+type HybridSet struct {
+	// Invariants:
+	//   • keys are in increasing order and len(keys) == len(cs),
+	//   • cs[i] holds the elements n with n>>chunkBits == keys[i]
+	//     as uint16 values,
+	//   • all containers are nonempty.
+	keys []int
+	cs   []container
+}
+
+const (
+	chunkBits   = 16
+	chunkSize   = 1 << chunkBits
+	chunkMask   = chunkSize - 1
+	maxArray    = 4096          // maximum size of an array container
+	bitmapBytes = chunkSize / 8 // size of a bitmap container
+)
+
+// A container holds the elements of a chunk of 2^16 possible elements.
+type container interface {
+	contains(x uint16) bool
+	// add and remove return the updated container, which may have a new type;
+	// remove returns nil if the container becomes empty.
+	add(x uint16) container
+	remove(x uint16) container
+	// addRange and removeRange add or remove the elements from lo to hi-1,
+	// where 0 ≤ lo < hi ≤ 2^16, and return the updated container like add
+	// and remove.
+	addRange(lo, hi int) container
+	removeRange(lo, hi int) container
+	size() int
+	// next returns the next element y > x, or -1, where -1 ≤ x < 2^16.
+	next(x int) int
+	// prev returns the previous element y < x, or -1, where 0 ≤ x ≤ 2^16.
+	prev(x int) int
+	visit(base int, do func(n int) (skip bool)) (aborted bool)
+	// set returns a Set with the elements of the container;
+	// the caller must not modify it.
+	set() *Set
+	clone() container
+}
+
+// newContainer returns the most compact container holding the elements
+// of s, all of which must be less than 2^16, or nil if s is empty.
+// The container may take ownership of s.
+func newContainer(s *Set) container {
+	card := s.Size()
+	if card == 0 {
+		return nil
+	}
+	runs := 0
+	visitRuns(s.data, func(lo, hi int) (skip bool) {
+		runs++
+		return
+	})
+	if runsFit(runs, card) {
+		rc := make(runContainer, 0, runs)
+		visitRuns(s.data, func(lo, hi int) (skip bool) {
+			rc = append(rc, run{uint16(lo), uint16(hi - 1)})
+			return
+		})
+		return &rc
+	}
+	if card <= maxArray {
+		ac := make(arrayContainer, 0, card)
+		s.Visit(func(n int) (skip bool) {
+			ac = append(ac, uint16(n))
+			return
+		})
+		return &ac
+	}
+	return &bitmapContainer{bits: *s, card: card}
+}
+
+// runsFit tells if a run container with the given number of runs
+// is smaller than an array or bitmap container with card elements.
+func runsFit(runs, card int) bool {
+	return 4*runs+2 < min(2*card, bitmapBytes)
+}
+
+// arrayContainer holds the elements of a chunk in increasing order.
+// Containers of this type are used as *arrayContainer, which lets
+// add and remove update the slice in place.
+type arrayContainer []uint16
+
+// search returns the index of the first element y ≥ x in a, or len(a).
+func (a arrayContainer) search(x int) int {
+	lo, hi := 0, len(a)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if int(a[m]) < x {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	return lo
+}
+
+func (a arrayContainer) contains(x uint16) bool {
+	i := a.search(int(x))
+	return i < len(a) && a[i] == x
+}
+
+func (p *arrayContainer) add(x uint16) container {
+	a := *p
+	i := a.search(int(x))
+	if i < len(a) && a[i] == x {
+		return p
+	}
+	if len(a) == maxArray {
+		s := a.set()
+		s.Add(int(x))
+		return newContainer(s)
+	}
+	a = append(a, 0)
+	copy(a[i+1:], a[i:])
+	a[i] = x
+	*p = a
+	return p
+}
+
+func (p *arrayContainer) remove(x uint16) container {
+	a := *p
+	i := a.search(int(x))
+	if i == len(a) || a[i] != x {
+		return p
+	}
+	if len(a) == 1 {
+		return nil
+	}
+	copy(a[i:], a[i+1:])
+	*p = a[:len(a)-1]
+	return p
+}
+
+func (p *arrayContainer) addRange(lo, hi int) container {
+	rc := p.runs()
+	return rc.addRange(lo, hi)
+}
+
+func (p *arrayContainer) removeRange(lo, hi int) container {
+	a := *p
+	i, j := a.search(lo), a.search(hi)
+	if i == j {
+		return p
+	}
+	if i == 0 && j == len(a) {
+		return nil
+	}
+	*p = append(a[:i], a[j:]...)
+	return p
+}
+
+// runs returns the runs of consecutive elements in a.
+func (a arrayContainer) runs() *runContainer {
+	var rc runContainer
+	for i, x := range a {
+		if i > 0 && x == a[i-1]+1 {
+			rc[len(rc)-1].last = x
+		} else {
+			rc = append(rc, run{x, x})
+		}
+	}
+	return &rc
+}
+
+func (a arrayContainer) size() int { return len(a) }
+
+func (a arrayContainer) next(x int) int {
+	if i := a.search(x + 1); i < len(a) {
+		return int(a[i])
+	}
+	return -1
+}
+
+func (a arrayContainer) prev(x int) int {
+	if i := a.search(x); i > 0 {
+		return int(a[i-1])
+	}
+	return -1
+}
+
+func (a arrayContainer) visit(base int, do func(n int) (skip bool)) (aborted bool) {
+	for _, x := range a {
+		if do(base + int(x)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a arrayContainer) set() *Set {
+	s := new(Set)
+	if len(a) > 0 {
+		s.realloc(int(a[len(a)-1])>>shift + 1)
+	}
+	for _, x := range a {
+		s.data[x>>shift] |= 1 << (x & mask)
+	}
+	return s
+}
+
+func (a arrayContainer) clone() container {
+	c := append(arrayContainer(nil), a...)
+	return &c
+}
+
+// bitmapContainer holds the elements of a chunk in a Set.
+type bitmapContainer struct {
+	bits Set
+	card int // number of elements
+}
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.bits.Contains(int(x))
+}
+
+func (b *bitmapContainer) add(x uint16) container {
+	if b.bits.Contains(int(x)) {
+		return b
+	}
+	b.bits.Add(int(x))
+	b.card++
+	if b.card%maxArray == 0 { // Check for runs now and then.
+		return newContainer(&b.bits)
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) container {
+	if !b.bits.Contains(int(x)) {
+		return b
+	}
+	b.bits.Delete(int(x))
+	b.card--
+	if b.card <= maxArray {
+		return newContainer(&b.bits)
+	}
+	return b
+}
+
+func (b *bitmapContainer) addRange(lo, hi int) container {
+	b.bits.AddRange(lo, hi)
+	return newContainer(&b.bits)
+}
+
+func (b *bitmapContainer) removeRange(lo, hi int) container {
+	b.bits.DeleteRange(lo, hi)
+	return newContainer(&b.bits)
+}
+
+func (b *bitmapContainer) size() int { return b.card }
+
+func (b *bitmapContainer) next(x int) int { return b.bits.Next(x) }
+
+func (b *bitmapContainer) prev(x int) int { return b.bits.Prev(x) }
+
+func (b *bitmapContainer) visit(base int, do func(n int) (skip bool)) (aborted bool) {
+	return b.bits.Visit(func(n int) (skip bool) {
+		return do(base + n)
+	})
+}
+
+func (b *bitmapContainer) set() *Set { return &b.bits }
+
+func (b *bitmapContainer) clone() container {
+	c := &bitmapContainer{card: b.card}
+	c.bits.Set(&b.bits)
+	return c
+}
+
+// run is a range of consecutive elements from first to last.
+type run struct {
+	first, last uint16
+}
+
+// runContainer holds the elements of a chunk as a list of runs
+// in increasing order, separated by at least one non-element.
+// Containers of this type are used as *runContainer.
+type runContainer []run
+
+// search returns the index of the first run r with r.last ≥ x, or len(rc).
+func (rc runContainer) search(x int) int {
+	lo, hi := 0, len(rc)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if int(rc[m].last) < x {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	return lo
+}
+
+func (rc runContainer) contains(x uint16) bool {
+	i := rc.search(int(x))
+	return i < len(rc) && rc[i].first <= x
+}
+
+func (p *runContainer) add(x uint16) container {
+	rc := *p
+	i := rc.search(int(x))
+	if i < len(rc) && rc[i].first <= x {
+		return p
+	}
+	left := i > 0 && int(rc[i-1].last)+1 == int(x)
+	right := i < len(rc) && int(rc[i].first)-1 == int(x)
+	switch {
+	case left && right: // Merge two runs.
+		rc[i-1].last = rc[i].last
+		*p = append(rc[:i], rc[i+1:]...)
+		return p
+	case left:
+		rc[i-1].last = x
+		return p
+	case right:
+		rc[i].first = x
+		return p
+	}
+	rc = append(rc, run{})
+	copy(rc[i+1:], rc[i:])
+	rc[i] = run{x, x}
+	*p = rc
+	return p.convert()
+}
+
+func (p *runContainer) remove(x uint16) container {
+	rc := *p
+	i := rc.search(int(x))
+	if i == len(rc) || rc[i].first > x {
+		return p
+	}
+	switch r := rc[i]; {
+	case r.first == r.last:
+		if len(rc) == 1 {
+			return nil
+		}
+		rc = append(rc[:i], rc[i+1:]...)
+	case x == r.first:
+		rc[i].first++
+	case x == r.last:
+		rc[i].last--
+	default: // Split the run.
+		rc = append(rc, run{})
+		copy(rc[i+1:], rc[i:])
+		rc[i].last = x - 1
+		rc[i+1].first = x + 1
+	}
+	*p = rc
+	return p.convert()
+}
+
+func (p *runContainer) addRange(lo, hi int) container {
+	rc := *p
+	// Runs i to j-1 overlap or touch the range and are merged with it.
+	i := rc.search(lo - 1)
+	j := rc.search(hi)
+	if j < len(rc) && int(rc[j].first) <= hi {
+		j++
+	}
+	r := run{uint16(lo), uint16(hi - 1)}
+	if i < j {
+		r.first = uint16(min(lo, int(rc[i].first)))
+		r.last = uint16(max(hi-1, int(rc[j-1].last)))
+		rc[i] = r
+		*p = append(rc[:i+1], rc[j:]...)
+		return p.convert()
+	}
+	rc = append(rc, run{})
+	copy(rc[i+1:], rc[i:])
+	rc[i] = r
+	*p = rc
+	return p.convert()
+}
+
+func (p *runContainer) removeRange(lo, hi int) container {
+	rc := *p
+	// Runs i to j-1 overlap the range.
+	i := rc.search(lo)
+	j := rc.search(hi - 1)
+	if j < len(rc) && int(rc[j].first) < hi {
+		j++
+	}
+	if i == j {
+		return p
+	}
+	// Keep the parts of the first and last runs outside the range.
+	var keep [2]run
+	n := 0
+	if int(rc[i].first) < lo {
+		keep[n] = run{rc[i].first, uint16(lo - 1)}
+		n++
+	}
+	if int(rc[j-1].last) >= hi {
+		keep[n] = run{uint16(hi), rc[j-1].last}
+		n++
+	}
+	if n == 0 && j-i == len(rc) {
+		return nil
+	}
+	if n > j-i { // Split a run.
+		rc = append(rc, run{})
+		copy(rc[j+1:], rc[j:])
+		j++
+	}
+	copy(rc[i:], keep[:n])
+	*p = append(rc[:i+n], rc[j:]...)
+	return p.convert()
+}
+
+// convert returns p, or a container of another type with the same
+// elements if that is smaller.
+func (p *runContainer) convert() container {
+	card := p.size()
+	switch {
+	case runsFit(len(*p), card):
+		return p
+	case card <= maxArray:
+		ac := make(arrayContainer, 0, card)
+		for _, r := range *p {
+			for x := int(r.first); x <= int(r.last); x++ {
+				ac = append(ac, uint16(x))
+			}
+		}
+		return &ac
+	}
+	return &bitmapContainer{bits: *p.set(), card: card}
+}
+
+func (rc runContainer) size() int {
+	n := 0
+	for _, r := range rc {
+		n += int(r.last) - int(r.first) + 1
+	}
+	return n
+}
+
+func (rc runContainer) next(x int) int {
+	x++
+	i := rc.search(x)
+	switch {
+	case i == len(rc):
+		return -1
+	case int(rc[i].first) <= x:
+		return x
+	}
+	return int(rc[i].first)
+}
+
+func (rc runContainer) prev(x int) int {
+	x--
+	if x < 0 {
+		return -1
+	}
+	i := rc.search(x)
+	if i < len(rc) && int(rc[i].first) <= x {
+		return x
+	}
+	if i > 0 {
+		return int(rc[i-1].last)
+	}
+	return -1
+}
+
+func (rc runContainer) visit(base int, do func(n int) (skip bool)) (aborted bool) {
+	for _, r := range rc {
+		for n := base + int(r.first); n <= base+int(r.last); n++ {
+			if do(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rc runContainer) set() *Set {
+	s := new(Set)
+	for _, r := range rc {
+		s.AddRange(int(r.first), int(r.last)+1)
+	}
+	return s
+}
+
+func (rc runContainer) clone() container {
+	c := append(runContainer(nil), rc...)
+	return &c
+}
+
+// NewHybridSet creates a new set with the given elements.
+// Negative numbers are not included in the set.
+func NewHybridSet(n ...int) *HybridSet {
+	s := new(HybridSet)
+	for _, e := range n {
+		s.Add(e)
+	}
+	return s
+}
+
+// search returns the index of the first key k ≥ key in s, or len(s.keys).
+func (s *HybridSet) search(key int) int {
+	lo, hi := 0, len(s.keys)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if s.keys[m] < key {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	return lo
+}
+
+// Contains tells if n is an element of the set.
+func (s *HybridSet) Contains(n int) bool {
+	if n < 0 {
+		return false
+	}
+	key := n >> chunkBits
+	i := s.search(key)
+	return i < len(s.keys) && s.keys[i] == key && s.cs[i].contains(uint16(n))
+}
+
+// Equal tells if s1 and s2 contain the same elements.
+func (s1 *HybridSet) Equal(s2 *HybridSet) bool {
+	if s1 == s2 {
+		return true
+	}
+	if len(s1.keys) != len(s2.keys) {
+		return false
+	}
+	for i, key := range s1.keys {
+		if key != s2.keys[i] || !equalContainers(s1.cs[i], s2.cs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subset tells if s1 is a subset of s2.
+func (s1 *HybridSet) Subset(s2 *HybridSet) bool {
+	j := 0
+	for i, key := range s1.keys {
+		for j < len(s2.keys) && s2.keys[j] < key {
+			j++
+		}
+		if j == len(s2.keys) || s2.keys[j] != key || !subsetContainer(s1.cs[i], s2.cs[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalContainers tells if a and b contain the same elements.
+func equalContainers(a, b container) bool {
+	switch a := a.(type) {
+	case *arrayContainer:
+		if b, ok := b.(*arrayContainer); ok {
+			x, y := *a, *b
+			if len(x) != len(y) {
+				return false
+			}
+			for i := range x {
+				if x[i] != y[i] {
+					return false
+				}
+			}
+			return true
+		}
+	case *runContainer:
+		if b, ok := b.(*runContainer); ok {
+			x, y := *a, *b
+			if len(x) != len(y) {
+				return false
+			}
+			for i := range x {
+				if x[i] != y[i] {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return a.size() == b.size() && subsetContainer(a, b)
+}
+
+// subsetContainer tells if a is a subset of b. Only if a is a bitmap
+// container, or a is a run container and b isn't, does it build a Set
+// from the elements of a or b.
+func subsetContainer(a, b container) bool {
+	if a.size() > b.size() {
+		return false
+	}
+	switch a := a.(type) {
+	case *arrayContainer:
+		for _, x := range *a {
+			if !b.contains(x) {
+				return false
+			}
+		}
+		return true
+	case *runContainer:
+		if b, ok := b.(*runContainer); ok {
+			// Each run of a must be within a run of b.
+			y, j := *b, 0
+			for _, r := range *a {
+				for j < len(y) && y[j].last < r.last {
+					j++
+				}
+				if j == len(y) || y[j].first > r.first {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return a.set().Subset(b.set())
+}
+
+// Max returns the maximum element of the set;
+// it panics if the set is empty.
+func (s *HybridSet) Max() int {
+	if len(s.keys) == 0 {
+		panic("max not defined for empty set")
+	}
+	i := len(s.keys) - 1
+	return s.keys[i]<<chunkBits + s.cs[i].prev(chunkSize)
+}
+
+// Min returns the minimum element of the set;
+// it panics if the set is empty.
+func (s *HybridSet) Min() int {
+	if len(s.keys) == 0 {
+		panic("min not defined for empty set")
+	}
+	return s.keys[0]<<chunkBits + s.cs[0].next(-1)
+}
+
+// Size returns the number of elements in the set.
+func (s *HybridSet) Size() int {
+	n := 0
+	for _, c := range s.cs {
+		n += c.size()
+	}
+	return n
+}
+
+// Empty tells if the set is empty.
+func (s *HybridSet) Empty() bool {
+	return len(s.keys) == 0
+}
+
+// Next returns the next element n, n > m, in the set,
+// or -1 if there is no such element.
+func (s *HybridSet) Next(m int) int {
+	if m == MaxInt {
+		return -1
+	}
+	m = max(0, m+1) // Look for smallest element n ≥ m.
+	key := m >> chunkBits
+	i := s.search(key)
+	if i < len(s.keys) && s.keys[i] == key {
+		if x := s.cs[i].next(m&chunkMask - 1); x != -1 {
+			return key<<chunkBits + x
+		}
+		i++
+	}
+	if i < len(s.keys) {
+		return s.keys[i]<<chunkBits + s.cs[i].next(-1)
+	}
+	return -1
+}
+
+// Prev returns the previous element n, n < m, in the set,
+// or -1 if there is no such element.
+func (s *HybridSet) Prev(m int) int {
+	if m <= 0 {
+		return -1
+	}
+	m-- // Look for largest element n ≤ m.
+	key := m >> chunkBits
+	i := s.search(key)
+	if i < len(s.keys) && s.keys[i] == key {
+		if x := s.cs[i].prev(m&chunkMask + 1); x != -1 {
+			return key<<chunkBits + x
+		}
+	}
+	if i > 0 {
+		return s.keys[i-1]<<chunkBits + s.cs[i-1].prev(chunkSize)
+	}
+	return -1
+}
+
+// Visit calls the do function for each element of s in numerical order.
+// If do returns true, Visit returns immediately, skipping any remaining
+// elements, and returns true. The behavior of Visit is undefined
+// if do changes the set.
+func (s *HybridSet) Visit(do func(n int) (skip bool)) (aborted bool) {
+	for i, c := range s.cs {
+		if c.visit(s.keys[i]<<chunkBits, do) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the set. The elements
+// are listed in ascending order. Runs of at least three consecutive
+// elements from a to b are given as a..b.
+func (s *HybridSet) String() string {
+	return formatSet(s.Visit)
+}
+
+// Add adds n to s and returns a pointer to the updated set.
+// A negative n will not be added.
+func (s *HybridSet) Add(n int) *HybridSet {
+	if n < 0 {
+		return s
+	}
+	key := n >> chunkBits
+	i := s.search(key)
+	if i < len(s.keys) && s.keys[i] == key {
+		s.cs[i] = s.cs[i].add(uint16(n))
+		return s
+	}
+	s.insert(i, key, &arrayContainer{uint16(n)})
+	return s
+}
+
+// Delete removes n from s and returns a pointer to the updated set.
+func (s *HybridSet) Delete(n int) *HybridSet {
+	if n < 0 {
+		return s
+	}
+	key := n >> chunkBits
+	i := s.search(key)
+	if i < len(s.keys) && s.keys[i] == key {
+		if s.cs[i] = s.cs[i].remove(uint16(n)); s.cs[i] == nil {
+			s.delete(i, i+1)
+		}
+	}
+	return s
+}
+
+// AddRange adds all integers from m to n-1 to s
+// and returns a pointer to the updated set.
+// Negative numbers will not be added.
+func (s *HybridSet) AddRange(m, n int) *HybridSet {
+	m = max(0, m)
+	if m >= n {
+		return s
+	}
+	n--
+	first, last := m>>chunkBits, n>>chunkBits
+	i := s.fill(first, last)
+	for key := first; key <= last; key, i = key+1, i+1 {
+		base := key << chunkBits
+		lo, hi := max(m, base)-base, min(n, base+chunkMask)-base+1
+		switch {
+		case lo == 0 && hi == chunkSize:
+			s.cs[i] = &runContainer{{0, chunkMask}}
+		case s.cs[i] == nil:
+			rc := runContainer{{uint16(lo), uint16(hi - 1)}}
+			s.cs[i] = rc.convert()
+		default:
+			s.cs[i] = s.cs[i].addRange(lo, hi)
+		}
+	}
+	return s
+}
+
+// DeleteRange removes all integers from m to n-1 from s
+// and returns a pointer to the updated set.
+func (s *HybridSet) DeleteRange(m, n int) *HybridSet {
+	m = max(0, m)
+	if m >= n {
+		return s
+	}
+	n--
+	i, j := s.search(m>>chunkBits), s.search(n>>chunkBits+1)
+	k := i // Containers i to k-1 are kept.
+	for ; i < j; i++ {
+		base := s.keys[i] << chunkBits
+		lo, hi := max(m, base)-base, min(n, base+chunkMask)-base+1
+		if lo == 0 && hi == chunkSize {
+			continue
+		}
+		if c := s.cs[i].removeRange(lo, hi); c != nil {
+			s.keys[k], s.cs[k] = s.keys[i], c
+			k++
+		}
+	}
+	s.delete(k, j)
+	return s
+}
+
+// And creates a new set that consists of all elements that belong
+// to both s1 and s2.
+func (s1 *HybridSet) And(s2 *HybridSet) *HybridSet {
+	return new(HybridSet).SetAnd(s1, s2)
+}
+
+// Or creates a new set that contains all elements that belong
+// to either s1 or s2.
+func (s1 *HybridSet) Or(s2 *HybridSet) *HybridSet {
+	return new(HybridSet).SetOr(s1, s2)
+}
+
+// Xor creates a new set that contains all elements that belong
+// to either s1 or s2, but not to both.
+func (s1 *HybridSet) Xor(s2 *HybridSet) *HybridSet {
+	return new(HybridSet).SetXor(s1, s2)
+}
+
+// AndNot creates a new set that consists of all elements that belong
+// to s1, but not to s2.
+func (s1 *HybridSet) AndNot(s2 *HybridSet) *HybridSet {
+	return new(HybridSet).SetAndNot(s1, s2)
+}
+
+// Set sets s to s1 and then returns a pointer to the updated set s.
+func (s *HybridSet) Set(s1 *HybridSet) *HybridSet {
+	if s == s1 {
+		return s
+	}
+	res := new(HybridSet)
+	for i, key := range s1.keys {
+		res.append(key, s1.cs[i].clone())
+	}
+	*s = *res
+	return s
+}
+
+// SetAnd sets s to the intersection s1 ∩ s2 and then returns a pointer to s.
+func (s *HybridSet) SetAnd(s1, s2 *HybridSet) *HybridSet {
+	return s.setOp(s1, s2, (*Set).SetAnd, false, false)
+}
+
+// SetAndNot sets s to the set difference s1 ∖ s2 and then returns a pointer to s.
+func (s *HybridSet) SetAndNot(s1, s2 *HybridSet) *HybridSet {
+	return s.setOp(s1, s2, (*Set).SetAndNot, true, false)
+}
+
+// SetOr sets s to the union s1 ∪ s2 and then returns a pointer to s.
+func (s *HybridSet) SetOr(s1, s2 *HybridSet) *HybridSet {
+	return s.setOp(s1, s2, (*Set).SetOr, true, true)
+}
+
+// SetXor sets s to the symmetric difference A ∆ B = (A ∪ B) ∖ (A ∩ B)
+// and then returns a pointer to s.
+func (s *HybridSet) SetXor(s1, s2 *HybridSet) *HybridSet {
+	return s.setOp(s1, s2, (*Set).SetXor, true, true)
+}
+
+// setOp sets s to the result of applying op chunk by chunk to s1 and s2.
+// Chunks only present in s1 are kept if keep1 is true,
+// and chunks only present in s2 are kept if keep2 is true.
+func (s *HybridSet) setOp(s1, s2 *HybridSet, op func(s, s1, s2 *Set) *Set, keep1, keep2 bool) *HybridSet {
+	res := new(HybridSet)
+	i, j := 0, 0
+	for i < len(s1.keys) || j < len(s2.keys) {
+		switch {
+		case j == len(s2.keys) || i < len(s1.keys) && s1.keys[i] < s2.keys[j]:
+			if keep1 {
+				res.append(s1.keys[i], s1.cs[i].clone())
+			}
+			i++
+		case i == len(s1.keys) || s2.keys[j] < s1.keys[i]:
+			if keep2 {
+				res.append(s2.keys[j], s2.cs[j].clone())
+			}
+			j++
+		default:
+			bits := op(new(Set), s1.cs[i].set(), s2.cs[j].set())
+			if c := newContainer(bits); c != nil {
+				res.append(s1.keys[i], c)
+			}
+			i++
+			j++
+		}
+	}
+	*s = *res
+	return s
+}
+
+// append adds the container c with the given key, which must be larger
+// than all keys in s, to s.
+func (s *HybridSet) append(key int, c container) {
+	s.keys = append(s.keys, key)
+	s.cs = append(s.cs, c)
+}
+
+// insert inserts the container c with the given key at index i.
+func (s *HybridSet) insert(i, key int, c container) {
+	s.keys = append(s.keys, 0)
+	copy(s.keys[i+1:], s.keys[i:])
+	s.keys[i] = key
+	s.cs = append(s.cs, nil)
+	copy(s.cs[i+1:], s.cs[i:])
+	s.cs[i] = c
+}
+
+// fill adds nil containers to s for the keys from first to last that
+// are missing, and returns the index of the container with key first.
+func (s *HybridSet) fill(first, last int) int {
+	i, j := s.search(first), s.search(last+1)
+	n := last - first + 1
+	d := n - (j - i)
+	if d == 0 {
+		return i
+	}
+	s.keys = append(s.keys, make([]int, d)...)
+	s.cs = append(s.cs, make([]container, d)...)
+	copy(s.keys[j+d:], s.keys[j:])
+	copy(s.cs[j+d:], s.cs[j:])
+	// Move the existing containers in place, from the back.
+	k := j - 1
+	for p := i + n - 1; p >= i; p-- {
+		key := first + p - i
+		if k >= i && s.keys[k] == key {
+			s.cs[p] = s.cs[k]
+			k--
+		} else {
+			s.cs[p] = nil
+		}
+		s.keys[p] = key
+	}
+	return i
+}
+
+// delete removes the containers with indices from i to j-1.
+func (s *HybridSet) delete(i, j int) {
+	if i == j {
+		return
+	}
+	s.keys = append(s.keys[:i], s.keys[j:]...)
+	n := copy(s.cs[i:], s.cs[j:])
+	for k := i + n; k < len(s.cs); k++ {
+		s.cs[k] = nil
+	}
+	s.cs = s.cs[:i+n]
+}
@@ -0,0 +1,315 @@
+package bit
+
+import (
+	"fmt"
+	"testing"
+)
+
+// CheckHybridInvariants checks that the invariants for s hold.
+func CheckHybridInvariants(t *testing.T, msg string, s *HybridSet) {
+	if len(s.keys) != len(s.cs) {
+		t.Errorf("Invariant for %s: %d keys and %d containers", msg, len(s.keys), len(s.cs))
+		return
+	}
+	for i, key := range s.keys {
+		if key < 0 || i > 0 && key <= s.keys[i-1] {
+			t.Errorf("Invariant for %s: keys %v not increasing", msg, s.keys)
+		}
+		switch c := s.cs[i].(type) {
+		case *arrayContainer:
+			a := *c
+			if len(a) == 0 || len(a) > maxArray {
+				t.Errorf("Invariant for %s: array container of size %d", msg, len(a))
+			}
+			for j := 1; j < len(a); j++ {
+				if a[j] <= a[j-1] {
+					t.Errorf("Invariant for %s: array container %v not increasing", msg, a)
+				}
+			}
+		case *bitmapContainer:
+			if size := c.bits.Size(); size != c.card || size <= maxArray {
+				t.Errorf("Invariant for %s: bitmap container of size %d, card %d", msg, size, c.card)
+			}
+			CheckInvariants(t, msg, &c.bits)
+		case *runContainer:
+			rc := *c
+			if len(rc) == 0 {
+				t.Errorf("Invariant for %s: empty run container", msg)
+			}
+			for j, r := range rc {
+				if r.first > r.last || j > 0 && int(r.first) <= int(rc[j-1].last)+1 {
+					t.Errorf("Invariant for %s: run container %v not valid", msg, rc)
+				}
+			}
+		}
+	}
+}
+
+func TestHybridSet(t *testing.T) {
+	const base = 1 << 30
+	for _, x := range []struct {
+		s    *HybridSet
+		str  string
+		keys int
+	}{
+		{NewHybridSet(), "{}", 0},
+		{NewHybridSet(-1), "{}", 0},
+		{NewHybridSet(0, 1), "{0 1}", 1},
+		{NewHybridSet(base+1, base+2, base+3), "{1073741825..1073741827}", 1},
+		{NewHybridSet(base, 1, -5), "{1 1073741824}", 2},
+		{new(HybridSet).AddRange(base-10, base+10).DeleteRange(base-5, base+5), "{1073741814..1073741818 1073741829..1073741833}", 2},
+		{new(HybridSet).AddRange(-10, 3).Delete(1), "{0 2}", 1},
+		{new(HybridSet).AddRange(10, 3*chunkSize).DeleteRange(5, 3*chunkSize), "{}", 0},
+		{new(HybridSet).AddRange(0, 3*chunkSize).DeleteRange(1, 3*chunkSize-1), "{0 196607}", 2},
+	} {
+		s := x.s
+		if str := s.String(); str != x.str {
+			t.Errorf("s.String() = %q; want %q", str, x.str)
+		}
+		if keys := len(s.keys); keys != x.keys {
+			t.Errorf("%v uses %d containers; want %d", s, keys, x.keys)
+		}
+		CheckHybridInvariants(t, "HybridSet", s)
+	}
+}
+
+func TestHybridSetContainers(t *testing.T) {
+	const base = 5 * chunkSize
+	s := new(HybridSet)
+	// Every third element, which gives an array and then a bitmap.
+	for n := base; n < base+chunkSize; n += 3 {
+		s.Add(n)
+		if _, ok := s.cs[0].(*arrayContainer); ok != (s.cs[0].size() <= maxArray) {
+			t.Errorf("Add(%d): container %T of size %d", n, s.cs[0], s.cs[0].size())
+		}
+	}
+	CheckHybridInvariants(t, "Add", s)
+	if size := s.Size(); size != chunkSize/3+1 {
+		t.Errorf("Size() = %d; want %d", size, chunkSize/3+1)
+	}
+	for n := base; n < base+chunkSize; n += 3 {
+		if s.Delete(n).Empty() {
+			break
+		}
+		if _, ok := s.cs[0].(*bitmapContainer); ok != (s.Size() > maxArray) {
+			t.Errorf("Delete(%d): container %T of size %d", n, s.cs[0], s.Size())
+		}
+	}
+	if !s.Empty() {
+		t.Errorf("%v.Empty() = false; want true", s)
+	}
+
+	// A long range is stored as a run container.
+	s.AddRange(base+10, base+chunkSize-10)
+	if _, ok := s.cs[0].(*runContainer); !ok {
+		t.Errorf("AddRange: container %T; want *runContainer", s.cs[0])
+	}
+	s.Delete(base + 100)
+	if _, ok := s.cs[0].(*runContainer); !ok {
+		t.Errorf("Delete: container %T; want *runContainer", s.cs[0])
+	}
+	CheckHybridInvariants(t, "AddRange", s)
+	if size := s.Size(); size != chunkSize-21 {
+		t.Errorf("Size() = %d; want %d", size, chunkSize-21)
+	}
+	for _, n := range []int{base + 9, base + 100, base + chunkSize - 10} {
+		if s.Contains(n) {
+			t.Errorf("%v.Contains(%d) = true; want false", s, n)
+		}
+	}
+	for _, n := range []int{base + 10, base + 99, base + 101, base + chunkSize - 11} {
+		if !s.Contains(n) {
+			t.Errorf("%v.Contains(%d) = false; want true", s, n)
+		}
+	}
+	if min, max := s.Min(), s.Max(); min != base+10 || max != base+chunkSize-11 {
+		t.Errorf("%v.Min(), Max() = %d, %d; want %d, %d", s, min, max, base+10, base+chunkSize-11)
+	}
+}
+
+func TestHybridSetAddRuns(t *testing.T) {
+	// Consecutive elements added one at a time, in order and out of order,
+	// end up in a single run.
+	const n = 10000
+	for _, step := range []int{1, 7919} {
+		s := new(HybridSet)
+		for i := 0; i < n; i++ {
+			s.Add(i * step % n)
+		}
+		CheckHybridInvariants(t, "Add", s)
+		rc, ok := s.cs[0].(*runContainer)
+		if !ok {
+			t.Errorf("Add with step %d: container %T; want *runContainer", step, s.cs[0])
+			continue
+		}
+		if runs := fmt.Sprint(*rc); runs != "[{0 9999}]" {
+			t.Errorf("Add with step %d: runs = %s; want [{0 9999}]", step, runs)
+		}
+	}
+}
+
+func TestHybridSetRuns(t *testing.T) {
+	s := new(HybridSet).AddRange(10, 20).AddRange(30, 40)
+	for _, x := range []struct {
+		add  bool
+		n    int
+		runs string
+	}{
+		{true, 20, "[{10 20} {30 39}]"},         // extend to the right
+		{true, 9, "[{9 20} {30 39}]"},           // extend to the left
+		{true, 25, "[{9 20} {25 25} {30 39}]"},  // new run
+		{false, 25, "[{9 20} {30 39}]"},         // remove run
+		{false, 35, "[{9 20} {30 34} {36 39}]"}, // split run
+		{true, 35, "[{9 20} {30 39}]"},          // merge runs
+		{false, 9, "[{10 20} {30 39}]"},         // shrink from the left
+		{false, 39, "[{10 20} {30 38}]"},        // shrink from the right
+		{true, 15, "[{10 20} {30 38}]"},         // already there
+		{false, 25, "[{10 20} {30 38}]"},        // not there
+	} {
+		if x.add {
+			s.Add(x.n)
+		} else {
+			s.Delete(x.n)
+		}
+		if runs := fmt.Sprint(*s.cs[0].(*runContainer)); runs != x.runs {
+			t.Errorf("Add/Delete(%d): runs = %s; want %s", x.n, runs, x.runs)
+		}
+	}
+	CheckHybridInvariants(t, "runs", s)
+
+	// Adding next to a run doesn't allocate.
+	s = new(HybridSet).AddRange(1000, 2000)
+	n := 2000
+	if allocs := testing.AllocsPerRun(100, func() { s.Add(n); n++ }); allocs > 0 {
+		t.Errorf("Add next to a run allocates %v times", allocs)
+	}
+
+	// Many short runs are converted to an array.
+	s = new(HybridSet).AddRange(0, 2)
+	for n := 4; n < 40; n += 2 {
+		s.Add(n)
+	}
+	if _, ok := s.cs[0].(*arrayContainer); !ok {
+		t.Errorf("container %T; want *arrayContainer", s.cs[0])
+	}
+	CheckHybridInvariants(t, "short runs", s)
+}
+
+func TestHybridSetRange(t *testing.T) {
+	// Add and delete random ranges, some of them spanning several chunks,
+	// and compare with a Set.
+	s, b := new(HybridSet), new(Set)
+	x := 1
+	for i := 0; i < 2000; i++ {
+		x = (x*1103515245 + 12345) & 0x7fffffff
+		m := x % (4 * chunkSize)
+		x = (x*1103515245 + 12345) & 0x7fffffff
+		n := m + []int{1, 10, maxArray + 1, chunkSize}[i%4]*(x%3)
+		if x%5 < 2 {
+			s.DeleteRange(m, n)
+			b.DeleteRange(m, n)
+		} else {
+			s.AddRange(m, n)
+			b.AddRange(m, n)
+		}
+		CheckHybridInvariants(t, "AddRange/DeleteRange", s)
+		if i%100 == 99 {
+			CheckReadSet(t, fmt.Sprintf("range %d", i), s, b)
+		}
+	}
+	CheckReadSet(t, "range", s, b)
+
+	// A small range in one of many containers is updated in place.
+	s = new(HybridSet)
+	const chunks = 1000
+	for k := 0; k < chunks; k++ {
+		s.AddRange(k<<chunkBits, k<<chunkBits+10)
+	}
+	k := 0
+	if allocs := testing.AllocsPerRun(100, func() {
+		base := k % chunks << chunkBits
+		s.AddRange(base+10, base+12).DeleteRange(base+10, base+12)
+		k++
+	}); allocs > 0 {
+		t.Errorf("AddRange and DeleteRange allocate %v times", allocs)
+	}
+	CheckHybridInvariants(t, "AddRange", s)
+	if size := s.Size(); size != 10*chunks {
+		t.Errorf("Size() = %d; want %d", size, 10*chunks)
+	}
+}
+
+func TestHybridSetEqualAllocs(t *testing.T) {
+	a := NewHybridSet(1, 2, 3, chunkSize+100)
+	b := NewHybridSet(1, 2, 3, chunkSize+100)
+	c := new(HybridSet).AddRange(0, 5000)
+	d := new(HybridSet).AddRange(0, 5000)
+	cb := c.Or(b)
+	if allocs := testing.AllocsPerRun(10, func() {
+		if !a.Equal(b) || !a.Subset(b) || !c.Equal(d) || !c.Subset(d) || !a.Subset(cb) {
+			t.Errorf("Equal or Subset returned false")
+		}
+	}); allocs > 0 {
+		t.Errorf("Equal and Subset allocate %v times", allocs)
+	}
+}
+
+// BuildTestHybridSet builds a set of n somewhat random elements from 0..max-1.
+func BuildTestHybridSet(n, max int) *HybridSet {
+	s := new(HybridSet)
+	x := 1
+	for i := 0; i < n; i++ {
+		x = (x*1103515245 + 12345) & 0x7fffffff
+		s.Add(x % max)
+	}
+	return s
+}
+
+func TestHybridSetBinOp(t *testing.T) {
+	sets := []readSet{
+		NewHybridSet(),
+		NewHybridSet(1, 2),
+		NewHybridSet(2, 3, 1000, 3*chunkSize),
+		NewHybridSet(100, 300, 2*chunkSize, 2*chunkSize+1),
+		new(HybridSet).AddRange(600, 2*chunkSize),
+		new(HybridSet).AddRange(chunkSize-10, chunkSize+10),
+		new(HybridSet).AddRange(0, 3*chunkSize).DeleteRange(1, 2*chunkSize).DeleteRange(2*chunkSize+2, 3*chunkSize),
+		BuildTestHybridSet(2*maxArray, chunkSize),
+		BuildTestHybridSet(2*maxArray, 3*chunkSize),
+	}
+	CheckBinOps(t, sets, func(a, b readSet) []readSet {
+		x, y := a.(*HybridSet), b.(*HybridSet)
+		res := []readSet{x.And(y), x.Or(y), x.Xor(y), x.AndNot(y)}
+		for _, r := range res {
+			CheckHybridInvariants(t, "BinOp", r.(*HybridSet))
+		}
+		if s := new(HybridSet).Set(x).SetOr(x, y); !s.Equal(res[1].(*HybridSet)) {
+			t.Errorf("%v.SetOr(%v, %v) = %v; want %v", x, x, y, s, res[1])
+		}
+		return res
+	}, func(a, b readSet) bool {
+		return a.(*HybridSet).Equal(b.(*HybridSet))
+	}, func(a, b readSet) bool {
+		return a.(*HybridSet).Subset(b.(*HybridSet))
+	})
+	for _, a := range sets {
+		s := a.(*HybridSet)
+		if str := s.String(); s.Set(s).String() != str {
+			t.Errorf("%v.Set(itself) changed the set", s)
+		}
+	}
+
+	// Equal containers of different types.
+	a := new(HybridSet).AddRange(0, 10)
+	b := NewHybridSet(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	if !a.Equal(b) {
+		t.Errorf("%v.Equal(%v) = false; want true", a, b)
+	}
+	// The result must not share containers with the operands.
+	c := a.Or(NewHybridSet(100))
+	c.Add(5000)
+	c.Delete(5)
+	if s := a.String(); s != "{0..9}" {
+		t.Errorf("a = %s; want {0..9}", s)
+	}
+}
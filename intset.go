@@ -0,0 +1,287 @@
+package bit
+
+import (
+	"strconv"
+)
+
+// IntSet represents a mutable set of integers, which may be negative.
+// The zero value is an empty set ready to use.
+// An IntSet is made up of two bit sets, one for non-negative and one for
+// negative elements, and it occupies approximately max(|m|, |n|) bits,
+// where m and n are the minimum and maximum values that have been stored.
+type IntSet struct {
+	// Invariants:
+	//   • n belongs to set iff pos.Contains(n), for n ≥ 0,
+	//   • n belongs to set iff neg.Contains(^n) for n < 0,
+	//     where ^n == -n-1.
+	pos, neg Set
+}
+
+// NewIntSet creates a new set with the given elements.
+func NewIntSet(n ...int) *IntSet {
+	s := new(IntSet)
+	for _, e := range n {
+		s.Add(e)
+	}
+	return s
+}
+
+// Contains tells if n is an element of the set.
+func (s *IntSet) Contains(n int) bool {
+	if n < 0 {
+		return s.neg.Contains(^n)
+	}
+	return s.pos.Contains(n)
+}
+
+// Equal tells if s1 and s2 contain the same elements.
+func (s1 *IntSet) Equal(s2 *IntSet) bool {
+	return s1.pos.Equal(&s2.pos) && s1.neg.Equal(&s2.neg)
+}
+
+// Subset tells if s1 is a subset of s2.
+func (s1 *IntSet) Subset(s2 *IntSet) bool {
+	return s1.pos.Subset(&s2.pos) && s1.neg.Subset(&s2.neg)
+}
+
+// Max returns the maximum element of the set;
+// it panics if the set is empty.
+func (s *IntSet) Max() int {
+	if !s.pos.Empty() {
+		return s.pos.Max()
+	}
+	if !s.neg.Empty() {
+		return ^s.neg.Min()
+	}
+	panic("max not defined for empty set")
+}
+
+// Min returns the minimum element of the set;
+// it panics if the set is empty.
+func (s *IntSet) Min() int {
+	if !s.neg.Empty() {
+		return ^s.neg.Max()
+	}
+	if !s.pos.Empty() {
+		return s.pos.Min()
+	}
+	panic("min not defined for empty set")
+}
+
+// Size returns the number of elements in the set.
+// This method scans the set; to check if a set is empty,
+// consider using the more efficient Empty method.
+func (s *IntSet) Size() int {
+	return s.pos.Size() + s.neg.Size()
+}
+
+// Empty tells if the set is empty.
+func (s *IntSet) Empty() bool {
+	return s.pos.Empty() && s.neg.Empty()
+}
+
+// Next returns the next element n, n > m, in the set.
+// Since -1 may belong to the set, found tells if there is such an element.
+func (s *IntSet) Next(m int) (n int, found bool) {
+	if m < -1 {
+		// The smallest n > m is ^x for the largest x < ^m in neg.
+		if x := s.neg.Prev(^m); x != -1 {
+			return ^x, true
+		}
+		m = -1
+	}
+	if n = s.pos.Next(m); n != -1 {
+		return n, true
+	}
+	return 0, false
+}
+
+// Prev returns the previous element n, n < m, in the set.
+// Since -1 may belong to the set, found tells if there is such an element.
+func (s *IntSet) Prev(m int) (n int, found bool) {
+	if m > 0 {
+		if n = s.pos.Prev(m); n != -1 {
+			return n, true
+		}
+		m = 0
+	}
+	// The largest n < m is ^x for the smallest x > ^m in neg.
+	if x := s.neg.Next(^m); x != -1 {
+		return ^x, true
+	}
+	return 0, false
+}
+
+// Visit calls the do function for each element of s in numerical order.
+// If do returns true, Visit returns immediately, skipping any remaining
+// elements, and returns true. It is safe for do to add or delete
+// elements e, e ≤ n. The behavior of Visit is undefined if do changes
+// the set in any other way.
+func (s *IntSet) Visit(do func(n int) (skip bool)) (aborted bool) {
+	if !s.neg.Empty() {
+		for x := s.neg.Max(); x != -1; x = s.neg.Prev(x) {
+			if do(^x) {
+				return true
+			}
+		}
+	}
+	return s.pos.Visit(do)
+}
+
+// String returns a string representation of the set. The elements
+// are listed in ascending order. Runs of at least three consecutive
+// elements from a to b are given as a..b.
+func (s *IntSet) String() string {
+	return formatSet(s.Visit)
+}
+
+// formatSet returns a string representation, in the format used by String,
+// of the set whose elements are visited in ascending order by visit.
+func formatSet(visit func(do func(n int) (skip bool)) (aborted bool)) string {
+	var buf []byte
+	buf = append(buf, '{')
+	first := true
+	a, b := 0, -1 // Keep track of a range a..b of elements.
+	visit(func(n int) (skip bool) {
+		if !first && n == b+1 {
+			b++ // Increase current range from a..b to a..b+1.
+			return
+		}
+		buf = appendIntRange(buf, a, b)
+		a, b = n, n // Start new range.
+		first = false
+		return
+	})
+	buf = appendIntRange(buf, a, b)
+	if !first {
+		buf = buf[:len(buf)-1] // Remove trailing " ".
+	}
+	buf = append(buf, '}')
+	return string(buf)
+}
+
+// appendIntRange appends either "", "a ", "a b " or "a..b " to buf.
+func appendIntRange(buf []byte, a, b int) []byte {
+	switch {
+	case a > b:
+		return buf // Append nothing.
+	case a == b:
+		buf = strconv.AppendInt(buf, int64(a), 10)
+	case a+1 == b:
+		buf = strconv.AppendInt(buf, int64(a), 10)
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, int64(b), 10)
+	default:
+		buf = strconv.AppendInt(buf, int64(a), 10)
+		buf = append(buf, ".."...)
+		buf = strconv.AppendInt(buf, int64(b), 10)
+	}
+	return append(buf, ' ')
+}
+
+// Add adds n to s and returns a pointer to the updated set.
+func (s *IntSet) Add(n int) *IntSet {
+	if n < 0 {
+		s.neg.Add(^n)
+	} else {
+		s.pos.Add(n)
+	}
+	return s
+}
+
+// Delete removes n from s and returns a pointer to the updated set.
+func (s *IntSet) Delete(n int) *IntSet {
+	if n < 0 {
+		s.neg.Delete(^n)
+	} else {
+		s.pos.Delete(n)
+	}
+	return s
+}
+
+// AddRange adds all integers from m to n-1 to s
+// and returns a pointer to the updated set.
+func (s *IntSet) AddRange(m, n int) *IntSet {
+	if m >= n {
+		return s
+	}
+	s.pos.AddRange(m, n) // Negative numbers are not added.
+	if m < 0 {
+		// The integers m..min(n, 0)-1 are stored as ^(min(n, 0)-1)..^m.
+		s.neg.AddRange(^(min(n, 0) - 1), ^m+1)
+	}
+	return s
+}
+
+// DeleteRange removes all integers from m to n-1 from s
+// and returns a pointer to the updated set.
+func (s *IntSet) DeleteRange(m, n int) *IntSet {
+	if m >= n {
+		return s
+	}
+	s.pos.DeleteRange(m, n)
+	if m < 0 {
+		s.neg.DeleteRange(^(min(n, 0) - 1), ^m+1)
+	}
+	return s
+}
+
+// And creates a new set that consists of all elements that belong
+// to both s1 and s2.
+func (s1 *IntSet) And(s2 *IntSet) *IntSet {
+	return new(IntSet).SetAnd(s1, s2)
+}
+
+// Or creates a new set that contains all elements that belong
+// to either s1 or s2.
+func (s1 *IntSet) Or(s2 *IntSet) *IntSet {
+	return new(IntSet).SetOr(s1, s2)
+}
+
+// Xor creates a new set that contains all elements that belong
+// to either s1 or s2, but not to both.
+func (s1 *IntSet) Xor(s2 *IntSet) *IntSet {
+	return new(IntSet).SetXor(s1, s2)
+}
+
+// AndNot creates a new set that consists of all elements that belong
+// to s1, but not to s2.
+func (s1 *IntSet) AndNot(s2 *IntSet) *IntSet {
+	return new(IntSet).SetAndNot(s1, s2)
+}
+
+// Set sets s to s1 and then returns a pointer to the updated set s.
+func (s *IntSet) Set(s1 *IntSet) *IntSet {
+	s.pos.Set(&s1.pos)
+	s.neg.Set(&s1.neg)
+	return s
+}
+
+// SetAnd sets s to the intersection s1 ∩ s2 and then returns a pointer to s.
+func (s *IntSet) SetAnd(s1, s2 *IntSet) *IntSet {
+	s.pos.SetAnd(&s1.pos, &s2.pos)
+	s.neg.SetAnd(&s1.neg, &s2.neg)
+	return s
+}
+
+// SetAndNot sets s to the set difference s1 ∖ s2 and then returns a pointer to s.
+func (s *IntSet) SetAndNot(s1, s2 *IntSet) *IntSet {
+	s.pos.SetAndNot(&s1.pos, &s2.pos)
+	s.neg.SetAndNot(&s1.neg, &s2.neg)
+	return s
+}
+
+// SetOr sets s to the union s1 ∪ s2 and then returns a pointer to s.
+func (s *IntSet) SetOr(s1, s2 *IntSet) *IntSet {
+	s.pos.SetOr(&s1.pos, &s2.pos)
+	s.neg.SetOr(&s1.neg, &s2.neg)
+	return s
+}
+
+// SetXor sets s to the symmetric difference A ∆ B = (A ∪ B) ∖ (A ∩ B)
+// and then returns a pointer to s.
+func (s *IntSet) SetXor(s1, s2 *IntSet) *IntSet {
+	s.pos.SetXor(&s1.pos, &s2.pos)
+	s.neg.SetXor(&s1.neg, &s2.neg)
+	return s
+}
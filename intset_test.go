@@ -0,0 +1,117 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestIntSet(t *testing.T) {
+	for _, x := range []struct {
+		s        *IntSet
+		str      string
+		size     int
+		min, max int
+	}{
+		{NewIntSet(), "{}", 0, 0, 0},
+		{NewIntSet(-1), "{-1}", 1, -1, -1},
+		{NewIntSet(0, -1), "{-1 0}", 2, -1, 0},
+		{NewIntSet(1, -1, 0), "{-1..1}", 3, -1, 1},
+		{NewIntSet(-100, 5, -3, -4, -2), "{-100 -4..-2 5}", 5, -100, 5},
+		{NewIntSet(-65, 64), "{-65 64}", 2, -65, 64},
+		{NewIntSet(-5, -4), "{-5 -4}", 2, -5, -4},
+		{new(IntSet).AddRange(-70, 70), "{-70..69}", 140, -70, 69},
+		{new(IntSet).AddRange(-70, -10).DeleteRange(-60, -11), "{-70..-61 -11}", 11, -70, -11},
+		{new(IntSet).AddRange(-10, 10).DeleteRange(-5, 5), "{-10..-6 5..9}", 10, -10, 9},
+		{new(IntSet).AddRange(5, 10).Delete(7).Add(-7), "{-7 5 6 8 9}", 5, -7, 9},
+	} {
+		s := x.s
+		if str := s.String(); str != x.str {
+			t.Errorf("s.String() = %q; want %q", str, x.str)
+		}
+		if size := s.Size(); size != x.size {
+			t.Errorf("%v.Size() = %d; want %d", s, size, x.size)
+		}
+		if empty := s.Empty(); empty != (x.size == 0) {
+			t.Errorf("%v.Empty() = %t; want %t", s, empty, x.size == 0)
+		}
+		if x.size == 0 {
+			if !Panics((*IntSet).Min, s) || !Panics((*IntSet).Max, s) {
+				t.Errorf("Min() and Max() should panic for empty set.")
+			}
+			continue
+		}
+		if min := s.Min(); min != x.min {
+			t.Errorf("%v.Min() = %d; want %d", s, min, x.min)
+		}
+		if max := s.Max(); max != x.max {
+			t.Errorf("%v.Max() = %d; want %d", s, max, x.max)
+		}
+	}
+}
+
+func TestIntSetNextPrev(t *testing.T) {
+	s := NewIntSet(-100, -65, -64, -1, 0, 3, 64)
+	elems := []int{-100, -65, -64, -1, 0, 3, 64}
+	for m := -110; m <= 70; m++ {
+		var next, prev int
+		var hasNext, hasPrev, contains bool
+		for _, e := range elems {
+			if e == m {
+				contains = true
+			}
+			if e < m {
+				prev, hasPrev = e, true
+			}
+			if e > m && !hasNext {
+				next, hasNext = e, true
+			}
+		}
+		if n, ok := s.Next(m); ok != hasNext || ok && n != next {
+			t.Errorf("%v.Next(%d) = %d, %t; want %d, %t", s, m, n, ok, next, hasNext)
+		}
+		if n, ok := s.Prev(m); ok != hasPrev || ok && n != prev {
+			t.Errorf("%v.Prev(%d) = %d, %t; want %d, %t", s, m, n, ok, prev, hasPrev)
+		}
+		if c := s.Contains(m); c != contains {
+			t.Errorf("%v.Contains(%d) = %t; want %t", s, m, c, contains)
+		}
+	}
+	if n, ok := s.Next(MinInt); !ok || n != -100 {
+		t.Errorf("%v.Next(MinInt) = %d, %t; want -100, true", s, n, ok)
+	}
+	if n, ok := s.Prev(MaxInt); !ok || n != 64 {
+		t.Errorf("%v.Prev(MaxInt) = %d, %t; want 64, true", s, n, ok)
+	}
+
+	var res []int
+	s.Visit(func(n int) (skip bool) {
+		res = append(res, n)
+		return n == 0
+	})
+	if len(res) != 5 || res[0] != -100 || res[4] != 0 {
+		t.Errorf("%v.Visit() aborted at 0 visited %v", s, res)
+	}
+}
+
+func TestIntSetBinOp(t *testing.T) {
+	a, b := NewIntSet(-100, -2, -1, 1, 100), NewIntSet(-200, -2, 0, 1, 200)
+	for _, x := range []struct {
+		res  *IntSet
+		exp  string
+		name string
+	}{
+		{a.And(b), "{-2 1}", "And"},
+		{a.Or(b), "{-200 -100 -2..1 100 200}", "Or"},
+		{a.Xor(b), "{-200 -100 -1 0 100 200}", "Xor"},
+		{a.AndNot(b), "{-100 -1 100}", "AndNot"},
+	} {
+		if str := x.res.String(); str != x.exp {
+			t.Errorf("%v.%s(%v) = %s; want %s", a, x.name, b, str, x.exp)
+		}
+	}
+	if !a.And(b).Subset(a) || a.Subset(b) {
+		t.Errorf("Subset failed for %v and %v", a, b)
+	}
+	if !new(IntSet).Set(a).Equal(a) || a.Equal(b) {
+		t.Errorf("Equal failed for %v and %v", a, b)
+	}
+}
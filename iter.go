@@ -0,0 +1,48 @@
+//go:build go1.23
+// +build go1.23
+
+package bit
+
+import (
+	"iter"
+)
+
+// All returns an iterator over the elements of s in numerical order.
+// The same rules as for Visit apply to changes made to s during iteration.
+func (s *Set) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		s.Visit(func(n int) (skip bool) {
+			return !yield(n)
+		})
+	}
+}
+
+// Backward returns an iterator over the elements of s
+// in reverse numerical order.
+func (s *Set) Backward() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if s.Empty() {
+			return
+		}
+		for n := s.Max(); n != -1; n = s.Prev(n) {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// From returns an iterator over the elements n, n ≥ m, of s
+// in numerical order.
+func (s *Set) From(m int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if m < 0 {
+			m = 0
+		}
+		for n := s.Next(m - 1); n != -1; n = s.Next(n) {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
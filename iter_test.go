@@ -0,0 +1,72 @@
+//go:build go1.23
+// +build go1.23
+
+package bit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIter(t *testing.T) {
+	for _, x := range []struct {
+		s        *Set
+		m        int
+		all, bwd []int
+		from     []int
+	}{
+		{New(), 0, nil, nil, nil},
+		{New(0), 0, []int{0}, []int{0}, []int{0}},
+		{New(0), 1, []int{0}, []int{0}, nil},
+		{New(1, 2, 3), -1, []int{1, 2, 3}, []int{3, 2, 1}, []int{1, 2, 3}},
+		{New(1, 2, 3), 2, []int{1, 2, 3}, []int{3, 2, 1}, []int{2, 3}},
+		{New(63, 64, 300), 64, []int{63, 64, 300}, []int{300, 64, 63}, []int{64, 300}},
+		{New(63, 64, 300), 301, []int{63, 64, 300}, []int{300, 64, 63}, nil},
+		{New(100, 200), MinInt, []int{100, 200}, []int{200, 100}, []int{100, 200}},
+	} {
+		s := x.s
+		var all, bwd, from []int
+		for n := range s.All() {
+			all = append(all, n)
+		}
+		for n := range s.Backward() {
+			bwd = append(bwd, n)
+		}
+		for n := range s.From(x.m) {
+			from = append(from, n)
+		}
+		if !reflect.DeepEqual(all, x.all) {
+			t.Errorf("%v.All() yields %v; want %v", s, all, x.all)
+		}
+		if !reflect.DeepEqual(bwd, x.bwd) {
+			t.Errorf("%v.Backward() yields %v; want %v", s, bwd, x.bwd)
+		}
+		if !reflect.DeepEqual(from, x.from) {
+			t.Errorf("%v.From(%d) yields %v; want %v", s, x.m, from, x.from)
+		}
+	}
+}
+
+func TestIterBreak(t *testing.T) {
+	s := New(1, 2, 3, 100)
+	for _, x := range []struct {
+		name string
+		seq  func(func(int) bool)
+		exp  []int
+	}{
+		{"All", s.All(), []int{1, 2}},
+		{"Backward", s.Backward(), []int{100, 3}},
+		{"From", s.From(2), []int{2, 3}},
+	} {
+		var res []int
+		for n := range x.seq {
+			res = append(res, n)
+			if len(res) == 2 {
+				break
+			}
+		}
+		if !reflect.DeepEqual(res, x.exp) {
+			t.Errorf("%v.%s() with break yields %v; want %v", s, x.name, res, x.exp)
+		}
+	}
+}
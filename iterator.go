@@ -0,0 +1,125 @@
+package bit
+
+// Iterator positions used when an iterator is not at an element.
+const (
+	iterBefore = -1 // before the first element
+	iterAfter  = -2 // after the last element
+)
+
+// An Iterator is a cursor over the elements of a set in numerical order.
+// It keeps the current word of the set, which makes it cheap to step
+// to nearby elements and to seek forward, as in a leapfrog join
+// of several sets. The behavior of an Iterator is undefined if the set
+// is changed during iteration.
+type Iterator struct {
+	s *Set
+	n int    // current element, iterBefore or iterAfter
+	i int    // index of cached word, or -1 if none
+	w uint64 // cached word s.data[i]
+}
+
+// Iterator returns an iterator over s, positioned before the first element.
+func (s *Set) Iterator() *Iterator {
+	return &Iterator{s: s, n: iterBefore, i: -1}
+}
+
+// Value returns the current element,
+// or -1 if the iterator is not positioned at an element.
+func (it *Iterator) Value() int {
+	if it.n < 0 {
+		return -1
+	}
+	return it.n
+}
+
+// Next moves the iterator to the next element and tells if there is one.
+// If the iterator is positioned before the first element,
+// Next moves to the first element.
+func (it *Iterator) Next() bool {
+	switch it.n {
+	case iterAfter:
+		return false
+	case iterBefore:
+		return it.forward(0)
+	}
+	return it.forward(it.n + 1)
+}
+
+// Prev moves the iterator to the previous element and tells if there
+// is one. If the iterator is positioned after the last element,
+// Prev moves to the last element.
+func (it *Iterator) Prev() bool {
+	switch it.n {
+	case iterBefore:
+		return false
+	case iterAfter:
+		return it.backward(MaxInt)
+	case 0:
+		it.n = iterBefore
+		return false
+	}
+	return it.backward(it.n - 1)
+}
+
+// Seek moves the iterator to the smallest element n, n ≥ m,
+// and tells if there is such an element.
+func (it *Iterator) Seek(m int) bool {
+	return it.forward(max(0, m))
+}
+
+// word returns s.data[i], using the cached word if possible.
+func (it *Iterator) word(i int) uint64 {
+	if i != it.i {
+		it.i, it.w = i, it.s.data[i]
+	}
+	return it.w
+}
+
+// forward moves to the smallest element n, n ≥ m, where m ≥ 0.
+func (it *Iterator) forward(m int) bool {
+	d := it.s.data
+	i := m >> shift
+	if i >= len(d) {
+		it.n = iterAfter
+		return false
+	}
+	t := uint(m & mask)
+	w := it.word(i) >> t << t // Zero out bits for numbers < m.
+	for w == 0 {
+		i++
+		if i >= len(d) {
+			it.n = iterAfter
+			return false
+		}
+		w = it.word(i)
+	}
+	it.n = i<<shift + trailingZeros64(w)
+	return true
+}
+
+// backward moves to the largest element n, n ≤ m, where m ≥ 0.
+func (it *Iterator) backward(m int) bool {
+	d := it.s.data
+	if len(d) == 0 {
+		it.n = iterBefore
+		return false
+	}
+	i := m >> shift
+	var w uint64
+	if i >= len(d) {
+		i = len(d) - 1
+		w = it.word(i)
+	} else {
+		w = it.word(i) & bitMask(0, m&mask) // Zero out bits for numbers > m.
+	}
+	for w == 0 {
+		i--
+		if i < 0 {
+			it.n = iterBefore
+			return false
+		}
+		w = it.word(i)
+	}
+	it.n = i<<shift + len64(w) - 1
+	return true
+}
@@ -0,0 +1,88 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	for _, s := range []*Set{
+		New(),
+		New(0),
+		New(1, 2, 3),
+		New(63, 64),
+		New(0, 100, 200, 300),
+		BuildTestSet(100),
+	} {
+		// Forward and back again.
+		it := s.Iterator()
+		if v := it.Value(); v != -1 {
+			t.Errorf("%v.Iterator().Value() = %d; want -1", s, v)
+		}
+		for n := s.Next(-1); n != -1; n = s.Next(n) {
+			if !it.Next() || it.Value() != n {
+				t.Errorf("%v: Next() moved to %d; want %d", s, it.Value(), n)
+			}
+		}
+		if it.Next() || it.Value() != -1 {
+			t.Errorf("%v: Next() after last element moved to %d", s, it.Value())
+		}
+		if it.Next() {
+			t.Errorf("%v: Next() after end returned true", s)
+		}
+		for n := s.Prev(MaxInt); n != -1; n = s.Prev(n) {
+			if !it.Prev() || it.Value() != n {
+				t.Errorf("%v: Prev() moved to %d; want %d", s, it.Value(), n)
+			}
+		}
+		if it.Prev() || it.Value() != -1 {
+			t.Errorf("%v: Prev() before first element moved to %d", s, it.Value())
+		}
+		if !s.Empty() && (!it.Next() || it.Value() != s.Next(-1)) {
+			t.Errorf("%v: Next() after start moved to %d; want %d", s, it.Value(), s.Next(-1))
+		}
+
+		// Seek in both directions.
+		for _, m := range []int{MinInt, -1, 0, 1, 50, 64, 299, 300, 1000, 64, 2, 0} {
+			exp := s.Next(m - 1)
+			if m <= 0 {
+				exp = s.Next(-1)
+			}
+			if ok := it.Seek(m); ok != (exp != -1) || it.Value() != exp {
+				t.Errorf("%v: Seek(%d) = %t moved to %d; want %d", s, m, ok, it.Value(), exp)
+			}
+		}
+	}
+}
+
+// Leapfrog intersection of several sets.
+func TestIteratorJoin(t *testing.T) {
+	a := BuildTestSet(1000)
+	b := New().AddRange(100, 5000)
+	c := new(Set)
+	for n := 0; n < 8000; n += 3 {
+		c.Add(n)
+	}
+	exp := a.And(b).And(c)
+
+	its := []*Iterator{a.Iterator(), b.Iterator(), c.Iterator()}
+	res := new(Set)
+	if its[0].Next() {
+		m, agree := its[0].Value(), 1 // Number of iterators positioned at m.
+		for k := 1; ; k = (k + 1) % len(its) {
+			if !its[k].Seek(m) {
+				break
+			}
+			if v := its[k].Value(); v != m {
+				m, agree = v, 1
+				continue
+			}
+			if agree++; agree == len(its) {
+				res.Add(m)
+				m, agree = m+1, 0
+			}
+		}
+	}
+	if !res.Equal(exp) {
+		t.Errorf("leapfrog join = %v; want %v", res, exp)
+	}
+}
@@ -0,0 +1,159 @@
+package bit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// A JSONLayout specifies how a set is represented in JSON.
+type JSONLayout int
+
+const (
+	// JSONCompact lists the elements in ascending order as an array.
+	// Runs of at least three consecutive elements from a to b
+	// are given as pairs [a,b], e.g. [0,[2,4],7,8].
+	JSONCompact JSONLayout = iota
+
+	// JSONElements lists the elements in ascending order as an array,
+	// e.g. [0,2,3,4,7,8].
+	JSONElements
+
+	// JSONWords gives the binary encoding produced by MarshalBinary
+	// as a base64 string.
+	JSONWords
+)
+
+// JSON returns a json.Marshaler that encodes s using the given layout.
+// For example, json.Marshal(s.JSON(bit.JSONWords)) encodes s as a base64
+// string. All layouts are accepted by UnmarshalJSON.
+func (s *Set) JSON(layout JSONLayout) json.Marshaler {
+	return jsonSet{s, layout}
+}
+
+type jsonSet struct {
+	s      *Set
+	layout JSONLayout
+}
+
+func (x jsonSet) MarshalJSON() ([]byte, error) {
+	s := x.s
+	switch x.layout {
+	case JSONElements:
+		buf := []byte{'['}
+		s.Visit(func(n int) (skip bool) {
+			buf = strconv.AppendInt(buf, int64(n), 10)
+			buf = append(buf, ',')
+			return
+		})
+		return closeJSONArray(buf), nil
+	case JSONWords:
+		data, err := s.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(data) // A []byte is encoded as a base64 string.
+	}
+	buf := []byte{'['}
+	a, b := -1, -2 // Keep track of a range a..b of elements.
+	s.Visit(func(n int) (skip bool) {
+		if n == b+1 {
+			b++ // Increase current range from a..b to a..b+1.
+			return
+		}
+		buf = appendJSONRange(buf, a, b)
+		a, b = n, n // Start new range.
+		return
+	})
+	buf = appendJSONRange(buf, a, b)
+	return closeJSONArray(buf), nil
+}
+
+// appendJSONRange appends either "", "a,", "a,b," or "[a,b]," to buf.
+func appendJSONRange(buf []byte, a, b int) []byte {
+	switch {
+	case a > b:
+		return buf // Append nothing.
+	case a == b:
+		buf = strconv.AppendInt(buf, int64(a), 10)
+	case a+1 == b:
+		buf = strconv.AppendInt(buf, int64(a), 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(b), 10)
+	default:
+		buf = append(buf, '[')
+		buf = strconv.AppendInt(buf, int64(a), 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(b), 10)
+		buf = append(buf, ']')
+	}
+	return append(buf, ',')
+}
+
+// closeJSONArray replaces a trailing "," in buf, if any, with "]".
+func closeJSONArray(buf []byte) []byte {
+	if buf[len(buf)-1] == ',' {
+		buf = buf[:len(buf)-1]
+	}
+	return append(buf, ']')
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It uses the JSONCompact layout.
+func (s *Set) MarshalJSON() ([]byte, error) {
+	return jsonSet{s, JSONCompact}.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts all layouts produced by JSON. Elements may be listed
+// in any order. As is customary, a JSON null leaves s unchanged.
+// Like Parse, it rejects elements n ≥ 2^32 with a *FormatError.
+func (s *Set) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var bin []byte
+		if err := json.Unmarshal(data, &bin); err != nil {
+			return err
+		}
+		return s.UnmarshalBinary(bin)
+	}
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	res := new(Set)
+	for _, e := range elems {
+		var a, b int
+		if len(e) > 0 && e[0] == '[' {
+			var r []int
+			if err := json.Unmarshal(e, &r); err != nil {
+				return err
+			}
+			if len(r) != 2 {
+				return jsonError("range " + string(e) + " is not a pair")
+			}
+			a, b = r[0], r[1]
+		} else {
+			if err := json.Unmarshal(e, &a); err != nil {
+				return err
+			}
+			b = a
+		}
+		if a < 0 || b < a || b == MaxInt {
+			return jsonError("invalid element or range " + string(e))
+		}
+		if b>>shift >= maxDecodeWords {
+			return jsonError("element too large in " + string(e))
+		}
+		res.AddRange(a, b+1)
+	}
+	s.Set(res)
+	return nil
+}
+
+func jsonError(msg string) error {
+	return &FormatError{Format: "JSON", Msg: msg}
+}
@@ -0,0 +1,100 @@
+package bit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	for _, x := range []struct {
+		s                 *Set
+		compact, elements string
+	}{
+		{New(), "[]", "[]"},
+		{New(1), "[1]", "[1]"},
+		{New(1, 2), "[1,2]", "[1,2]"},
+		{New(0, 1, 2, 4, 5), "[[0,2],4,5]", "[0,1,2,4,5]"},
+		{New(0, 1, 2, 3, 5, 7, 8, 9), "[[0,3],5,[7,9]]", "[0,1,2,3,5,7,8,9]"},
+		{New(100, 200, 300), "[100,200,300]", "[100,200,300]"},
+	} {
+		s := x.s
+		for _, y := range []struct {
+			v   interface{}
+			exp string
+		}{
+			{s, x.compact},
+			{s.JSON(JSONCompact), x.compact},
+			{s.JSON(JSONElements), x.elements},
+		} {
+			res, err := json.Marshal(y.v)
+			if err != nil || string(res) != y.exp {
+				t.Errorf("json.Marshal(%v) = %s, %v; want %s, nil", s, res, err, y.exp)
+			}
+		}
+		for _, layout := range []JSONLayout{JSONCompact, JSONElements, JSONWords} {
+			data, err := json.Marshal(s.JSON(layout))
+			if err != nil {
+				t.Errorf("json.Marshal(%v.JSON(%d)) returned error %v", s, layout, err)
+				continue
+			}
+			res := New(5, 500)
+			if err := json.Unmarshal(data, res); err != nil || !res.Equal(s) {
+				t.Errorf("json.Unmarshal(%s) = %v, %v; want %v, nil", data, res, err, s)
+			}
+			CheckInvariants(t, "UnmarshalJSON", res)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	for _, x := range []struct {
+		data string
+		exp  *Set
+	}{
+		{"[]", New()},
+		{"null", New(1, 2, 3)},
+		{" [ 5, [1,3] , 2 ] ", New(1, 2, 3, 5)},
+		{"[[64,64]]", New(64)},
+		{`"AQA="`, New()},
+		{`"AQEBAAAAAAAAAA=="`, New(0)},
+	} {
+		res := New(1, 2, 3)
+		if err := json.Unmarshal([]byte(x.data), res); err != nil || !res.Equal(x.exp) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v; want %v, nil", x.data, res, err, x.exp)
+		}
+	}
+
+	for _, data := range []string{
+		"{}",
+		"1",
+		`"{1 2}"`,
+		`"AQEAAAAAAAAAAA=="`,
+		"[-1]",
+		"[1.5]",
+		"[[1]]",
+		"[[1,2,3]]",
+		"[[3,1]]",
+		`["1"]`,
+		"[1,",
+	} {
+		res := New(1, 2, 3)
+		if err := json.Unmarshal([]byte(data), res); err == nil {
+			t.Errorf("json.Unmarshal(%s) returned nil error", data)
+		}
+		if !res.Equal(New(1, 2, 3)) {
+			t.Errorf("json.Unmarshal(%s) with invalid input changed set to %v", data, res)
+		}
+	}
+
+	// Elements too large to allocate. On 32-bit platforms,
+	// they don't fit in an int and json reports the error.
+	for _, data := range []string{
+		"[9223372036854775806]",
+		"[[0,4294967296]]",
+	} {
+		err := json.Unmarshal([]byte(data), new(Set))
+		if _, ok := err.(*FormatError); !ok && (BitsPerWord == 64 || err == nil) {
+			t.Errorf("json.Unmarshal(%s) returned %v; want *FormatError", data, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package bit
+
+import (
+	"sort"
+)
+
+// Union returns a new set that contains all elements that belong
+// to at least one of the given sets.
+func Union(s ...*Set) *Set {
+	n := 0
+	for _, x := range s {
+		n = max(n, len(x.data))
+	}
+	d := make([]uint64, n)
+	for i := range d {
+		var w uint64
+		for _, x := range s {
+			if i < len(x.data) {
+				w |= x.data[i]
+			}
+		}
+		d[i] = w
+	}
+	return &Set{data: d}
+}
+
+// Intersection returns a new set that consists of all elements that
+// belong to all of the given sets. With no arguments, it returns
+// the empty set.
+func Intersection(s ...*Set) *Set {
+	if len(s) == 0 {
+		return New()
+	}
+	n := MaxInt
+	for _, x := range s {
+		n = min(n, len(x.data))
+	}
+	res := &Set{data: make([]uint64, n)}
+	for i := range res.data {
+		w := s[0].data[i]
+		for _, x := range s[1:] {
+			if w == 0 {
+				break
+			}
+			w &= x.data[i]
+		}
+		res.data[i] = w
+	}
+	res.trim()
+	return res
+}
+
+// AtLeast returns a new set that consists of all elements that belong
+// to at least k of the given sets. If k ≤ 1, it returns the union of
+// the sets, and if k > len(s), it returns the empty set.
+func AtLeast(k int, s ...*Set) *Set {
+	switch {
+	case k <= 1:
+		return Union(s...)
+	case k > len(s):
+		return New()
+	case k == len(s):
+		return Intersection(s...)
+	}
+	// An element in at least k sets belongs to one of the k longest sets.
+	lens := make([]int, len(s))
+	for i, x := range s {
+		lens[i] = len(x.data)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lens)))
+	res := &Set{data: make([]uint64, lens[k-1])}
+	// c[j] holds the elements found in at least j+1 sets so far.
+	c := make([]uint64, k)
+	for i := range res.data {
+		for j := range c {
+			c[j] = 0
+		}
+		for _, x := range s {
+			if i >= len(x.data) {
+				continue
+			}
+			w := x.data[i]
+			for j := k - 1; j > 0; j-- {
+				c[j] |= c[j-1] & w
+			}
+			c[0] |= w
+		}
+		res.data[i] = c[k-1]
+	}
+	res.trim()
+	return res
+}
@@ -0,0 +1,64 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestMulti(t *testing.T) {
+	a := New(1, 2, 3, 100)
+	b := New(2, 3, 4, 200)
+	c := New(3, 4, 5, 100, 300)
+	e := New()
+	for _, x := range []struct {
+		res  *Set
+		exp  string
+		name string
+	}{
+		{Union(), "{}", "Union()"},
+		{Union(e), "{}", "Union(e)"},
+		{Union(a), "{1..3 100}", "Union(a)"},
+		{Union(a, b, c), "{1..5 100 200 300}", "Union(a, b, c)"},
+		{Union(e, c, e), "{3..5 100 300}", "Union(e, c, e)"},
+		{Intersection(), "{}", "Intersection()"},
+		{Intersection(a), "{1..3 100}", "Intersection(a)"},
+		{Intersection(a, b), "{2 3}", "Intersection(a, b)"},
+		{Intersection(a, b, c), "{3}", "Intersection(a, b, c)"},
+		{Intersection(a, c), "{3 100}", "Intersection(a, c)"},
+		{Intersection(a, e, c), "{}", "Intersection(a, e, c)"},
+		{Intersection(a, New(200)), "{}", "Intersection(a, {200})"},
+		{AtLeast(0, a, b), "{1..4 100 200}", "AtLeast(0, a, b)"},
+		{AtLeast(1, a, b, c), "{1..5 100 200 300}", "AtLeast(1, a, b, c)"},
+		{AtLeast(2, a, b, c), "{2..4 100}", "AtLeast(2, a, b, c)"},
+		{AtLeast(3, a, b, c), "{3}", "AtLeast(3, a, b, c)"},
+		{AtLeast(4, a, b, c), "{}", "AtLeast(4, a, b, c)"},
+		{AtLeast(2, a, e, c, e), "{3 100}", "AtLeast(2, a, e, c, e)"},
+		{AtLeast(2, New(1000), a, New(1000)), "{1000}", "AtLeast(2, {1000}, a, {1000})"},
+	} {
+		if str := x.res.String(); str != x.exp {
+			t.Errorf("%s = %s; want %s", x.name, str, x.exp)
+		}
+		CheckInvariants(t, x.name, x.res)
+	}
+
+	// Compare with pairwise operations.
+	sets := []*Set{BuildTestSet(1000), BuildTestSet(2000).Delete(2), BuildTestSet(500), New(1 << 16)}
+	or, and := new(Set), new(Set).Set(sets[0])
+	two := new(Set)
+	for _, s := range sets {
+		two.SetOr(two, new(Set).SetAnd(or, s))
+		or.SetOr(or, s)
+		and.SetAnd(and, s)
+	}
+	if res := Union(sets...); !res.Equal(or) {
+		t.Errorf("Union has size %d; want %d", res.Size(), or.Size())
+	}
+	if res := Intersection(sets...); !res.Equal(and) {
+		t.Errorf("Intersection has size %d; want %d", res.Size(), and.Size())
+	}
+	if res := AtLeast(2, sets...); !res.Equal(two) {
+		t.Errorf("AtLeast(2) has size %d; want %d", res.Size(), two.Size())
+	}
+	if a.String() != "{1..3 100}" || b.String() != "{2..4 200}" {
+		t.Errorf("arguments changed")
+	}
+}
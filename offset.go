@@ -0,0 +1,329 @@
+package bit
+
+// OffsetSet represents a mutable set of non-negative integers.
+// The zero value is an empty set ready to use.
+// Unlike a Set, whose words cover all numbers from 0 to the maximum
+// element, the words of an OffsetSet start at a movable offset that
+// grows downwards as smaller elements are added. An OffsetSet occupies
+// approximately 2(n-m) bits, where m and n are the minimum and maximum
+// values that have been stored in the set.
+type OffsetSet struct {
+	// Invariants:
+	//   • off is a non-negative multiple of bpw,
+	//   • n belongs to the set iff n ≥ off && set.Contains(n-off),
+	//   • at most half of the words in set are leading zero words,
+	//   • set has at least lead leading zero words.
+	off  int
+	lead int
+	set  Set
+}
+
+// NewOffsetSet creates a new set with the given elements.
+// Negative numbers are not included in the set.
+func NewOffsetSet(n ...int) *OffsetSet {
+	s := new(OffsetSet)
+	lo, hi := MaxInt, -1
+	for _, e := range n {
+		if e >= 0 {
+			lo, hi = min(lo, e), max(hi, e)
+		}
+	}
+	if hi < 0 {
+		return s
+	}
+	s.off = lo &^ mask
+	d := make([]uint64, (hi-s.off)>>shift+1)
+	for _, e := range n {
+		if e >= 0 {
+			e -= s.off
+			d[e>>shift] |= 1 << uint(e&mask)
+		}
+	}
+	s.set.data = d
+	return s
+}
+
+// Contains tells if n is an element of the set.
+func (s *OffsetSet) Contains(n int) bool {
+	return n >= s.off && s.set.Contains(n-s.off)
+}
+
+// Equal tells if s1 and s2 contain the same elements.
+func (s1 *OffsetSet) Equal(s2 *OffsetSet) bool {
+	lo1, hi1 := s1.span()
+	lo2, hi2 := s2.span()
+	for i, hi := min(lo1, lo2), max(hi1, hi2); i < hi; i++ {
+		if s1.word(i) != s2.word(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Max returns the maximum element of the set;
+// it panics if the set is empty.
+func (s *OffsetSet) Max() int {
+	return s.off + s.set.Max()
+}
+
+// Min returns the minimum element of the set;
+// it panics if the set is empty.
+func (s *OffsetSet) Min() int {
+	return s.off + s.set.Min()
+}
+
+// Size returns the number of elements in the set.
+// This method scans the set; to check if a set is empty,
+// consider using the more efficient Empty method.
+func (s *OffsetSet) Size() int {
+	return s.set.Size()
+}
+
+// Empty tells if the set is empty.
+func (s *OffsetSet) Empty() bool {
+	return s.set.Empty()
+}
+
+// Next returns the next element n, n > m, in the set,
+// or -1 if there is no such element.
+func (s *OffsetSet) Next(m int) int {
+	if m < s.off {
+		m = s.off - 1
+	}
+	if n := s.set.Next(m - s.off); n != -1 {
+		return n + s.off
+	}
+	return -1
+}
+
+// Prev returns the previous element n, n < m, in the set,
+// or -1 if there is no such element.
+func (s *OffsetSet) Prev(m int) int {
+	if m <= s.off {
+		return -1
+	}
+	if n := s.set.Prev(m - s.off); n != -1 {
+		return n + s.off
+	}
+	return -1
+}
+
+// Visit calls the do function for each element of s in numerical order.
+// If do returns true, Visit returns immediately, skipping any remaining
+// elements, and returns true. It is safe for do to delete elements e,
+// e ≤ n. The behavior of Visit is undefined if do changes the set
+// in any other way.
+func (s *OffsetSet) Visit(do func(n int) (skip bool)) (aborted bool) {
+	// Deleting elements may move the words of s, so look up
+	// each element from scratch.
+	for n := s.Next(-1); n != -1; n = s.Next(n) {
+		if do(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the set. The elements
+// are listed in ascending order. Runs of at least three consecutive
+// elements from a to b are given as a..b.
+func (s *OffsetSet) String() string {
+	return formatSet(s.Visit)
+}
+
+// Add adds n to s and returns a pointer to the updated set.
+// A negative n will not be added.
+func (s *OffsetSet) Add(n int) *OffsetSet {
+	if n < 0 {
+		return s
+	}
+	s.lower(n)
+	n -= s.off
+	s.set.Add(n)
+	s.lead = min(s.lead, n>>shift)
+	return s
+}
+
+// Delete removes n from s and returns a pointer to the updated set.
+func (s *OffsetSet) Delete(n int) *OffsetSet {
+	if n < s.off {
+		return s
+	}
+	n -= s.off
+	l := len(s.set.data)
+	s.set.Delete(n)
+	if d, i := s.set.data, n>>shift; i < l && (i >= len(d) || d[i] == 0) {
+		s.raise() // A word has become zero.
+	}
+	return s
+}
+
+// AddRange adds all integers from m to n-1 to s
+// and returns a pointer to the updated set.
+// Negative numbers will not be added.
+func (s *OffsetSet) AddRange(m, n int) *OffsetSet {
+	m = max(0, m)
+	if m >= n {
+		return s
+	}
+	s.lower(m)
+	s.set.AddRange(m-s.off, n-s.off)
+	s.lead = min(s.lead, (m-s.off)>>shift)
+	return s
+}
+
+// DeleteRange removes all integers from m to n-1 from s
+// and returns a pointer to the updated set.
+func (s *OffsetSet) DeleteRange(m, n int) *OffsetSet {
+	m = max(s.off, m)
+	if m >= n {
+		return s
+	}
+	s.set.DeleteRange(m-s.off, n-s.off)
+	s.raise()
+	return s
+}
+
+// And creates a new set that consists of all elements that belong
+// to both s1 and s2.
+func (s1 *OffsetSet) And(s2 *OffsetSet) *OffsetSet {
+	return new(OffsetSet).SetAnd(s1, s2)
+}
+
+// Or creates a new set that contains all elements that belong
+// to either s1 or s2.
+func (s1 *OffsetSet) Or(s2 *OffsetSet) *OffsetSet {
+	return new(OffsetSet).SetOr(s1, s2)
+}
+
+// Xor creates a new set that contains all elements that belong
+// to either s1 or s2, but not to both.
+func (s1 *OffsetSet) Xor(s2 *OffsetSet) *OffsetSet {
+	return new(OffsetSet).SetXor(s1, s2)
+}
+
+// AndNot creates a new set that consists of all elements that belong
+// to s1, but not to s2.
+func (s1 *OffsetSet) AndNot(s2 *OffsetSet) *OffsetSet {
+	return new(OffsetSet).SetAndNot(s1, s2)
+}
+
+// Set sets s to s1 and then returns a pointer to the updated set s.
+func (s *OffsetSet) Set(s1 *OffsetSet) *OffsetSet {
+	s.off, s.lead = s1.off, s1.lead
+	s.set.Set(&s1.set)
+	return s
+}
+
+// SetAnd sets s to the intersection s1 ∩ s2 and then returns a pointer to s.
+func (s *OffsetSet) SetAnd(s1, s2 *OffsetSet) *OffsetSet {
+	lo1, hi1 := s1.span()
+	lo2, hi2 := s2.span()
+	return s.setOp(s1, s2, max(lo1, lo2), min(hi1, hi2), func(a, b uint64) uint64 { return a & b })
+}
+
+// SetAndNot sets s to the set difference s1 ∖ s2 and then returns a pointer to s.
+func (s *OffsetSet) SetAndNot(s1, s2 *OffsetSet) *OffsetSet {
+	lo, hi := s1.span()
+	return s.setOp(s1, s2, lo, hi, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// SetOr sets s to the union s1 ∪ s2 and then returns a pointer to s.
+func (s *OffsetSet) SetOr(s1, s2 *OffsetSet) *OffsetSet {
+	lo, hi := unionSpan(s1, s2)
+	return s.setOp(s1, s2, lo, hi, func(a, b uint64) uint64 { return a | b })
+}
+
+// SetXor sets s to the symmetric difference A ∆ B = (A ∪ B) ∖ (A ∩ B)
+// and then returns a pointer to s.
+func (s *OffsetSet) SetXor(s1, s2 *OffsetSet) *OffsetSet {
+	lo, hi := unionSpan(s1, s2)
+	return s.setOp(s1, s2, lo, hi, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// setOp sets s to the set whose words, for word indices i from lo to hi-1,
+// are op(s1.word(i), s2.word(i)), and whose other words are zero.
+func (s *OffsetSet) setOp(s1, s2 *OffsetSet, lo, hi int, op func(a, b uint64) uint64) *OffsetSet {
+	if lo >= hi {
+		s.off, s.lead = 0, 0
+		s.set.realloc(0)
+		return s
+	}
+	d := make([]uint64, hi-lo)
+	for i := range d {
+		d[i] = op(s1.word(lo+i), s2.word(lo+i))
+	}
+	s.off, s.lead = lo<<shift, 0
+	s.set.data = d
+	s.set.trim()
+	s.raise()
+	return s
+}
+
+// span returns the word indices [lo, hi) covered by s.
+func (s *OffsetSet) span() (lo, hi int) {
+	lo = s.off >> shift
+	return lo, lo + len(s.set.data)
+}
+
+// unionSpan returns the smallest range of word indices that covers s1 and s2.
+func unionSpan(s1, s2 *OffsetSet) (lo, hi int) {
+	lo1, hi1 := s1.span()
+	lo2, hi2 := s2.span()
+	switch {
+	case lo1 == hi1:
+		return lo2, hi2
+	case lo2 == hi2:
+		return lo1, hi1
+	}
+	return min(lo1, lo2), max(hi1, hi2)
+}
+
+// word returns the word with index i, which holds the elements
+// from i<<shift to i<<shift+bpw-1.
+func (s *OffsetSet) word(i int) uint64 {
+	j := i - s.off>>shift
+	if j < 0 || j >= len(s.set.data) {
+		return 0
+	}
+	return s.set.data[j]
+}
+
+// raise raises the offset of s, removing the leading zero words,
+// if they make up more than half of the words. The count of leading
+// zero words starts from lead, so each zero word is scanned only once
+// until a smaller element is added, and the words are moved only when
+// the set has halved. This leaves room for lower to grow the set downwards.
+func (s *OffsetSet) raise() {
+	d := s.set.data
+	if len(d) == 0 {
+		s.off, s.lead = 0, 0
+		return
+	}
+	for d[s.lead] == 0 {
+		s.lead++
+	}
+	if 2*s.lead <= len(d) {
+		return
+	}
+	s.set.Rsh(s.lead << shift)
+	s.off += s.lead << shift
+	s.lead = 0
+}
+
+// lower lowers the offset of s, if necessary, so that off ≤ n.
+// To get linear amortized cost, the set at least doubles its size
+// when growing downwards.
+func (s *OffsetSet) lower(n int) {
+	if s.set.Empty() {
+		s.off, s.lead = n&^mask, 0
+		return
+	}
+	if n >= s.off {
+		return
+	}
+	k := max(s.off-n&^mask, len(s.set.data)<<shift)
+	k = min(k, s.off)
+	s.set.ShiftUp(0, k)
+	s.off -= k
+}
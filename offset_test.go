@@ -0,0 +1,154 @@
+package bit
+
+import (
+	"strconv"
+	"testing"
+)
+
+// CheckOffsetInvariants checks that the invariants for s hold.
+func CheckOffsetInvariants(t *testing.T, msg string, s *OffsetSet) {
+	if s.off < 0 || s.off&mask != 0 {
+		t.Errorf("Invariant for %s: off = %d; want non-negative multiple of %d", msg, s.off, bpw)
+	}
+	k := 0
+	for k < len(s.set.data) && s.set.data[k] == 0 {
+		k++
+	}
+	if 2*k > len(s.set.data) {
+		t.Errorf("Invariant for %s: %d of %d words are leading zeros", msg, k, len(s.set.data))
+	}
+	if s.lead < 0 || s.lead > k {
+		t.Errorf("Invariant for %s: lead = %d with %d leading zero words", msg, s.lead, k)
+	}
+	CheckInvariants(t, msg, &s.set)
+}
+
+func TestOffsetSet(t *testing.T) {
+	const base = 1 << 30
+	for _, x := range []struct {
+		s     *OffsetSet
+		str   string
+		words int
+	}{
+		{NewOffsetSet(), "{}", 0},
+		{NewOffsetSet(-1), "{}", 0},
+		{NewOffsetSet(0, 1), "{0 1}", 1},
+		{NewOffsetSet(base+1, base+2, base+3), "{1073741825..1073741827}", 1},
+		{NewOffsetSet(base+100, base+1, -5), "{1073741825 1073741924}", 2},
+		{new(OffsetSet).Add(base + 1000).Add(base), "{1073741824 1073742824}", 16},
+		{new(OffsetSet).AddRange(base-10, base+10).DeleteRange(base-5, base+5), "{1073741814..1073741818 1073741829..1073741833}", 2},
+		{new(OffsetSet).AddRange(-10, 3).Delete(1), "{0 2}", 1},
+	} {
+		s := x.s
+		if str := s.String(); str != x.str {
+			t.Errorf("s.String() = %q; want %q", str, x.str)
+		}
+		if words := len(s.set.data); words != x.words {
+			t.Errorf("%v uses %d words; want %d", s, words, x.words)
+		}
+		CheckOffsetInvariants(t, "OffsetSet", s)
+	}
+
+	// Grow downwards one element at a time.
+	s := new(OffsetSet)
+	for n := base + 1000; n >= base; n -= 3 {
+		s.Add(n)
+	}
+	if words := len(s.set.data); words > 2*(1000/bpw+1) {
+		t.Errorf("OffsetSet growing downwards uses %d words", words)
+	}
+	CheckOffsetInvariants(t, "Add", s)
+	if min, max := s.Min(), s.Max(); min != base+1 || max != base+1000 {
+		t.Errorf("%v.Min(), Max() = %d, %d; want %d, %d", s, min, max, base+1, base+1000)
+	}
+	for n := base - 10; n < base+1010; n++ {
+		contains := n >= base && n <= base+1000 && (base+1000-n)%3 == 0
+		if s.Contains(n) != contains {
+			t.Errorf("%v.Contains(%d) = %t; want %t", s, n, !contains, contains)
+		}
+	}
+}
+
+func TestOffsetSetSlidingWindow(t *testing.T) {
+	// Add increasing numbers and delete all but the latest 100.
+	const n, window = 1 << 16, 100
+	s := new(OffsetSet)
+	for i := 0; i < n; i++ {
+		s.Add(i)
+		s.Delete(i - window)
+		if words := len(s.set.data); words > 2*(window/bpw+2) {
+			t.Fatalf("OffsetSet with %v uses %d words", s, words)
+		}
+	}
+	CheckOffsetInvariants(t, "sliding window", s)
+	if min, max := s.Min(), s.Max(); min != n-window || max != n-1 {
+		t.Errorf("%v.Min(), Max() = %d, %d; want %d, %d", s, min, max, n-window, n-1)
+	}
+	if size := s.Size(); size != window {
+		t.Errorf("%v.Size() = %d; want %d", s, size, window)
+	}
+
+	// The same with DeleteRange.
+	s = new(OffsetSet)
+	for i := 0; i < n; i += 10 {
+		s.AddRange(i, i+10)
+		s.DeleteRange(0, i-window)
+	}
+	if words := len(s.set.data); words > 2*(window/bpw+2) {
+		t.Errorf("OffsetSet with %v uses %d words", s, words)
+	}
+	CheckOffsetInvariants(t, "sliding window", s)
+}
+
+func TestOffsetSetVisit(t *testing.T) {
+	// Deleting visited elements raises the offset while Visit is running.
+	for _, del := range []func(s *OffsetSet, n int){
+		func(s *OffsetSet, n int) { s.Delete(n) },
+		func(s *OffsetSet, n int) { s.DeleteRange(0, n+1) },
+	} {
+		s := NewOffsetSet(1000, 1070, 1140, 1210, 1280, 1350)
+		res := ""
+		s.Visit(func(n int) (skip bool) {
+			del(s, n)
+			res += strconv.Itoa(n) + " "
+			return
+		})
+		if exp := "1000 1070 1140 1210 1280 1350 "; res != exp {
+			t.Errorf("Visit with deletions visited %q; want %q", res, exp)
+		}
+		if !s.Empty() {
+			t.Errorf("%v.Empty() = false; want true", s)
+		}
+		CheckOffsetInvariants(t, "Visit", s)
+	}
+}
+
+func TestOffsetSetBinOp(t *testing.T) {
+	sets := []readSet{
+		NewOffsetSet(),
+		NewOffsetSet(1, 2),
+		NewOffsetSet(2, 3, 1000),
+		NewOffsetSet(100, 300),
+		NewOffsetSet(500, 700),
+		new(OffsetSet).AddRange(600, 2000),
+		new(OffsetSet).AddRange(5000, 6000),
+	}
+	CheckBinOps(t, sets, func(a, b readSet) []readSet {
+		x, y := a.(*OffsetSet), b.(*OffsetSet)
+		res := []readSet{x.And(y), x.Or(y), x.Xor(y), x.AndNot(y)}
+		for _, r := range res {
+			CheckOffsetInvariants(t, "BinOp", r.(*OffsetSet))
+		}
+		if s := new(OffsetSet).Set(x).SetOr(x, y); !s.Equal(res[1].(*OffsetSet)) {
+			t.Errorf("%v.SetOr(%v, %v) = %v; want %v", x, x, y, s, res[1])
+		}
+		return res
+	}, func(a, b readSet) bool {
+		return a.(*OffsetSet).Equal(b.(*OffsetSet))
+	}, nil)
+
+	a := new(OffsetSet).Add(1000).Add(10).Delete(10)
+	if b := NewOffsetSet(1000); !a.Equal(b) {
+		t.Errorf("%v.Equal(%v) = false; want true", a, b)
+	}
+}
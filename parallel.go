@@ -0,0 +1,162 @@
+package bit
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	// parallelMin is the smallest number of words for which
+	// the parallel operations use more than one goroutine.
+	parallelMin = 1 << 14
+	// parallelAlign is the alignment, in words, of the chunks given
+	// to different goroutines, chosen to avoid false sharing.
+	parallelAlign = 1 << 9
+)
+
+// ParallelSize returns the number of elements in the set.
+// It's equivalent to Size, but splits the work across
+// GOMAXPROCS goroutines if the set is large.
+func (s *Set) ParallelSize() int {
+	d := s.data
+	n := 0
+	for _, r := range parallel(len(d), func(lo, hi int) int {
+		n := 0
+		for _, w := range d[lo:hi] {
+			n += onesCount64(w)
+		}
+		return n
+	}) {
+		n += r
+	}
+	return n
+}
+
+// ParallelSetAnd sets s to the intersection s1 ∩ s2 and then returns
+// a pointer to s. It's equivalent to SetAnd, but splits the work across
+// GOMAXPROCS goroutines if the sets are large.
+func (s *Set) ParallelSetAnd(s1, s2 *Set) *Set {
+	a, b := s1.data, s2.data
+	d := s.parallelResize(min(len(a), len(b)), s1, s2)
+	return s.parallelTrim(parallel(len(d), func(lo, hi int) (last int) {
+		for i := lo; i < hi; i++ {
+			if d[i] = a[i] & b[i]; d[i] != 0 {
+				last = i + 1
+			}
+		}
+		return
+	}))
+}
+
+// ParallelSetAndNot sets s to the set difference s1 ∖ s2 and then returns
+// a pointer to s. It's equivalent to SetAndNot, but splits the work across
+// GOMAXPROCS goroutines if the sets are large.
+func (s *Set) ParallelSetAndNot(s1, s2 *Set) *Set {
+	a, b := s1.data, s2.data
+	m := min(len(a), len(b))
+	d := s.parallelResize(len(a), s1, s2)
+	return s.parallelTrim(parallel(len(d), func(lo, hi int) (last int) {
+		i := lo
+		for ; i < min(hi, m); i++ {
+			if d[i] = a[i] &^ b[i]; d[i] != 0 {
+				last = i + 1
+			}
+		}
+		if i < hi { // Words only in s1, the last of which is nonzero.
+			copy(d[i:hi], a[i:hi])
+			last = hi
+		}
+		return
+	}))
+}
+
+// ParallelSetOr sets s to the union s1 ∪ s2 and then returns a pointer
+// to s. It's equivalent to SetOr, but splits the work across
+// GOMAXPROCS goroutines if the sets are large.
+func (s *Set) ParallelSetOr(s1, s2 *Set) *Set {
+	return s.parallelSetOrXor(s1, s2, false)
+}
+
+// ParallelSetXor sets s to the symmetric difference A ∆ B = (A ∪ B) ∖ (A ∩ B)
+// and then returns a pointer to s. It's equivalent to SetXor, but splits
+// the work across GOMAXPROCS goroutines if the sets are large.
+func (s *Set) ParallelSetXor(s1, s2 *Set) *Set {
+	return s.parallelSetOrXor(s1, s2, true)
+}
+
+// parallelSetOrXor computes the symmetric difference if xor is true,
+// and the union otherwise.
+func (s *Set) parallelSetOrXor(s1, s2 *Set, xor bool) *Set {
+	// Swap, if necessary, to make s1 shorter than s2.
+	if len(s1.data) > len(s2.data) {
+		s1, s2 = s2, s1
+	}
+	a, b := s1.data, s2.data
+	m := len(a)
+	d := s.parallelResize(len(b), s1, s2)
+	return s.parallelTrim(parallel(len(d), func(lo, hi int) (last int) {
+		i := lo
+		for ; i < min(hi, m); i++ {
+			if xor {
+				d[i] = a[i] ^ b[i]
+			} else {
+				d[i] = a[i] | b[i]
+			}
+			if d[i] != 0 {
+				last = i + 1
+			}
+		}
+		if i < hi { // Words only in s2, the last of which is nonzero.
+			copy(d[i:hi], b[i:hi])
+			last = hi
+		}
+		return
+	}))
+}
+
+// parallelResize gives s length n, keeping the old values if s is one
+// of s1 and s2, and returns s.data.
+func (s *Set) parallelResize(n int, s1, s2 *Set) []uint64 {
+	if s == s1 || s == s2 {
+		s.resize(n)
+	} else {
+		s.realloc(n)
+	}
+	return s.data
+}
+
+// parallelTrim shortens s to the largest of the chunk lengths in last,
+// each of which is one more than the index of the last nonzero word
+// in a chunk, or 0 if all words in the chunk are zero, and returns s.
+func (s *Set) parallelTrim(last []int) *Set {
+	n := 0
+	for _, l := range last {
+		n = max(n, l)
+	}
+	s.data = s.data[:n]
+	return s
+}
+
+// parallel splits the range of word indices [0, n) into chunks,
+// calls do for each chunk [lo, hi) in a separate goroutine,
+// and returns the results in order. Small ranges are handled
+// by a single call in the current goroutine.
+func parallel(n int, do func(lo, hi int) int) []int {
+	procs := runtime.GOMAXPROCS(0)
+	if n < parallelMin || procs == 1 {
+		return []int{do(0, n)}
+	}
+	size := (n/procs + parallelAlign - 1) &^ (parallelAlign - 1)
+	res := make([]int, (n+size-1)/size)
+	var wg sync.WaitGroup
+	for k := range res {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			lo := k * size
+			res[k] = do(lo, min(lo+size, n))
+		}(k)
+	}
+	wg.Wait()
+	return res
+}
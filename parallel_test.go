@@ -0,0 +1,52 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	const n = 4 * parallelMin * bpw // number of bits
+	big := BuildTestSet(n / 8)
+	sets := []*Set{
+		New(),
+		New(1, 2, 3),
+		big,
+		new(Set).Set(big).DeleteRange(n/2, n),
+		new(Set).AddRange(n/3, n+5),
+		new(Set).AddRange(0, n).DeleteRange(1000, n-bpw),
+	}
+	for _, a := range sets {
+		if size, exp := a.ParallelSize(), a.Size(); size != exp {
+			t.Errorf("ParallelSize() = %d; want %d", size, exp)
+		}
+		for _, b := range sets {
+			for _, x := range []struct {
+				par, seq func(s, s1, s2 *Set) *Set
+				name     string
+			}{
+				{(*Set).ParallelSetAnd, (*Set).SetAnd, "ParallelSetAnd"},
+				{(*Set).ParallelSetAndNot, (*Set).SetAndNot, "ParallelSetAndNot"},
+				{(*Set).ParallelSetOr, (*Set).SetOr, "ParallelSetOr"},
+				{(*Set).ParallelSetXor, (*Set).SetXor, "ParallelSetXor"},
+			} {
+				exp := x.seq(new(Set), a, b)
+				res := x.par(new(Set).AddRange(0, n/2), a, b)
+				if !res.Equal(exp) {
+					t.Errorf("%s: size %d; want %d", x.name, res.Size(), exp.Size())
+				}
+				CheckInvariants(t, x.name, res)
+
+				// The result may be one of the operands.
+				a1, b1 := new(Set).Set(a), new(Set).Set(b)
+				if res := x.par(a1, a1, b); !res.Equal(exp) {
+					t.Errorf("%s with s == s1: size %d; want %d", x.name, res.Size(), exp.Size())
+				}
+				CheckInvariants(t, x.name, a1)
+				if res := x.par(b1, a, b1); !res.Equal(exp) {
+					t.Errorf("%s with s == s2: size %d; want %d", x.name, res.Size(), exp.Size())
+				}
+				CheckInvariants(t, x.name, b1)
+			}
+		}
+	}
+}
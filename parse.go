@@ -0,0 +1,109 @@
+package bit
+
+import (
+	"strconv"
+)
+
+// A SyntaxError describes a syntax error in the input to Parse.
+type SyntaxError struct {
+	Msg    string // description of the error
+	Offset int    // error occurred after reading Offset bytes
+}
+
+func (e *SyntaxError) Error() string {
+	return "bit: " + e.Msg + " at offset " + strconv.Itoa(e.Offset)
+}
+
+// Parse returns the set represented by the string str, which should
+// have the format produced by String, e.g. "{1..3 5 8}". Elements are
+// separated by white space and may be given in any order. A range a..b
+// denotes all integers from a to b. If str is not a valid representation
+// of a set, or if it has an element n ≥ 2^32, which would take more than
+// 512 MiB of memory, Parse returns a *SyntaxError.
+func Parse(str string) (*Set, error) {
+	p := &parser{str: str}
+	s := new(Set)
+	p.skipSpace()
+	if !p.consume("{") {
+		return nil, p.error("expected '{'")
+	}
+	for {
+		p.skipSpace()
+		if p.consume("}") {
+			break
+		}
+		a, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		b := a
+		p.skipSpace()
+		if p.consume("..") {
+			p.skipSpace()
+			start := p.pos
+			if b, err = p.number(); err != nil {
+				return nil, err
+			}
+			if b < a {
+				return nil, &SyntaxError{"empty range", start}
+			}
+		}
+		s.AddRange(a, b+1)
+	}
+	p.skipSpace()
+	if p.pos < len(p.str) {
+		return nil, p.error("unexpected data after '}'")
+	}
+	return s, nil
+}
+
+// parser holds the state of Parse.
+type parser struct {
+	str string
+	pos int // current offset in str
+}
+
+func (p *parser) error(msg string) error {
+	return &SyntaxError{msg, p.pos}
+}
+
+// skipSpace advances past any white space.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.str) {
+		switch p.str[p.pos] {
+		case ' ', '\t', '\n', '\v', '\f', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume advances past tok and returns true if the input starts with tok.
+func (p *parser) consume(tok string) bool {
+	if len(p.str)-p.pos < len(tok) || p.str[p.pos:p.pos+len(tok)] != tok {
+		return false
+	}
+	p.pos += len(tok)
+	return true
+}
+
+// number parses a non-negative decimal integer that is small enough
+// to be an element of a decoded set.
+func (p *parser) number() (int, error) {
+	start := p.pos
+	for p.pos < len(p.str) && '0' <= p.str[p.pos] && p.str[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos == len(p.str) {
+			return 0, p.error("unexpected end of input")
+		}
+		return 0, p.error("expected number")
+	}
+	n, err := strconv.Atoi(p.str[start:p.pos])
+	if err != nil || n == MaxInt || n>>shift >= maxDecodeWords {
+		return 0, &SyntaxError{"number out of range", start}
+	}
+	return n, nil
+}
@@ -0,0 +1,81 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, x := range []struct {
+		str string
+		exp *Set
+	}{
+		{"{}", New()},
+		{" { } ", New()},
+		{"{0}", New(0)},
+		{"{1 2}", New(1, 2)},
+		{"{0..2 4 5}", New(0, 1, 2, 4, 5)},
+		{"{0..3 5 7..9}", New(0, 1, 2, 3, 5, 7, 8, 9)},
+		{"\t{\n100  200\r300 }\n", New(100, 200, 300)},
+		{"{5 1 3..3 2..4}", New(1, 2, 3, 4, 5)},
+		{"{1 .. 3}", New(1, 2, 3)},
+		{"{62..65}", New(62, 63, 64, 65)},
+	} {
+		res, err := Parse(x.str)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error %v", x.str, err)
+			continue
+		}
+		if !res.Equal(x.exp) {
+			t.Errorf("Parse(%q) = %v; want %v", x.str, res, x.exp)
+		}
+		CheckInvariants(t, "Parse", res)
+	}
+
+	for _, s := range []*Set{
+		New(),
+		New(1, 3),
+		New(0, 2, 3, 5),
+		New(0, 1, 2, 3, 5, 7, 8, 9),
+		New().AddRange(10, 1000).Add(2000),
+	} {
+		res, err := Parse(s.String())
+		if err != nil || !res.Equal(s) {
+			t.Errorf("Parse(%q) = %v, %v; want %v, nil", s.String(), res, err, s)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, x := range []struct {
+		str    string
+		offset int
+	}{
+		{"", 0},
+		{"  ", 2},
+		{"1 2", 0},
+		{"{", 1},
+		{"{1 2", 4},
+		{"{1,2}", 2},
+		{"{-1}", 1},
+		{"{1..}", 4},
+		{"{..3}", 1},
+		{"{1...3}", 4},
+		{"{3..1}", 4},
+		{"{1} x", 4},
+		{"{1}}", 3},
+		{"{99999999999999999999}", 1},
+		{"{9223372036854775806}", 1},
+		{"{4294967296}", 1},
+		{"{0..4294967296}", 4},
+	} {
+		_, err := Parse(x.str)
+		e, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Parse(%q) returned %v; want *SyntaxError", x.str, err)
+			continue
+		}
+		if e.Offset != x.offset {
+			t.Errorf("Parse(%q) error offset = %d; want %d", x.str, e.Offset, x.offset)
+		}
+	}
+}
@@ -0,0 +1,560 @@
+package bit
+
+// PersistentSet represents an immutable set of non-negative integers.
+// The zero value is an empty set ready to use.
+//
+// A PersistentSet is never changed. Instead, With, Without and the
+// binary operations return a new set, which shares all unchanged parts
+// with the original ones. This makes it cheap to keep old versions of
+// a set: a snapshot is just a pointer to the current version, and it can
+// be read by several goroutines while another goroutine creates new
+// versions.
+//
+// The elements are stored in a tree of fixed-size blocks of words.
+// With and Without copy the O(log n) blocks on the path from the root
+// to the element, and the binary operations only visit the parts of
+// the trees that differ.
+type PersistentSet struct {
+	// Invariants:
+	//   • root is a leaf if height == 0,
+	//   • a nil node or child represents an empty block,
+	//   • no node in the tree is empty,
+	//   • if height > 0, root has a nonempty child other than kids[0].
+	root   *pnode
+	height int
+}
+
+const (
+	leafShift = 4 // log2 of words per leaf
+	leafWords = 1 << leafShift
+	leafLog   = leafShift + shift // log2 of bits per leaf
+	fanShift  = 5                 // log2 of children per interior node
+	fanout    = 1 << fanShift
+)
+
+// A pnode is a node in the tree of a PersistentSet. Nodes are immutable
+// once they are part of a set.
+type pnode struct {
+	kids  []*pnode // fanout children of an interior node
+	words []uint64 // leafWords words of a leaf
+}
+
+// childShift returns log2 of the number of bits covered by
+// the children of an interior node at height h > 0.
+func childShift(h int) uint {
+	return uint(leafLog + fanShift*(h-1))
+}
+
+// fits tells if n, n ≥ 0, can be stored in a tree of height h.
+func fits(n, h int) bool {
+	return uint(n)>>(childShift(h)+fanShift) == 0
+}
+
+// NewPersistentSet creates a new set with the given elements.
+// Negative numbers are not included in the set.
+func NewPersistentSet(n ...int) *PersistentSet {
+	return New(n...).Persistent()
+}
+
+// Persistent returns a persistent set with the elements of s.
+func (s *Set) Persistent() *PersistentSet {
+	h := 0
+	for len(s.data) > leafWords<<uint(fanShift*h) {
+		h++
+	}
+	return &PersistentSet{root: buildNode(s.data, h), height: h}
+}
+
+// buildNode returns a node at height h with the words in d.
+func buildNode(d []uint64, h int) *pnode {
+	if h == 0 {
+		for _, w := range d {
+			if w != 0 {
+				x := &pnode{words: make([]uint64, leafWords)}
+				copy(x.words, d)
+				return x
+			}
+		}
+		return nil
+	}
+	cw := leafWords << uint(fanShift*(h-1)) // words per child
+	var x *pnode
+	for i := 0; i < fanout && i*cw < len(d); i++ {
+		if c := buildNode(d[i*cw:min(len(d), (i+1)*cw)], h-1); c != nil {
+			if x == nil {
+				x = &pnode{kids: make([]*pnode, fanout)}
+			}
+			x.kids[i] = c
+		}
+	}
+	return x
+}
+
+// Mutable returns a new Set with the elements of s.
+func (s *PersistentSet) Mutable() *Set {
+	res := new(Set)
+	if s.root == nil {
+		return res
+	}
+	res.data = make([]uint64, s.Max()>>shift+1)
+	copyWords(res.data, s.root, s.height, 0)
+	return res
+}
+
+// copyWords copies the words of x, a node at height h whose first word
+// has index i, to d.
+func copyWords(d []uint64, x *pnode, h, i int) {
+	if x == nil {
+		return
+	}
+	if h == 0 {
+		copy(d[i:], x.words)
+		return
+	}
+	cw := leafWords << uint(fanShift*(h-1))
+	for j, c := range x.kids {
+		if i+j*cw >= len(d) {
+			return
+		}
+		copyWords(d, c, h-1, i+j*cw)
+	}
+}
+
+// Contains tells if n is an element of the set.
+func (s *PersistentSet) Contains(n int) bool {
+	if n < 0 || !fits(n, s.height) {
+		return false
+	}
+	x := s.root
+	for h := s.height; h > 0 && x != nil; h-- {
+		x = x.kids[n>>childShift(h)&(fanout-1)]
+	}
+	if x == nil {
+		return false
+	}
+	i := n & (1<<leafLog - 1)
+	return x.words[i>>shift]&(1<<uint(i&mask)) != 0
+}
+
+// Equal tells if s1 and s2 contain the same elements.
+func (s1 *PersistentSet) Equal(s2 *PersistentSet) bool {
+	return s1.height == s2.height && equalNodes(s1.root, s2.root)
+}
+
+func equalNodes(a, b *pnode) bool {
+	switch {
+	case a == b:
+		return true
+	case a == nil || b == nil:
+		return false
+	case a.words != nil:
+		for i, w := range a.words {
+			if w != b.words[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for i, c := range a.kids {
+		if !equalNodes(c, b.kids[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subset tells if s1 is a subset of s2.
+func (s1 *PersistentSet) Subset(s2 *PersistentSet) bool {
+	if s1.height > s2.height {
+		return s1.root == nil
+	}
+	return subsetNodes(lift(s1.root, s1.height, s2.height), s2.root)
+}
+
+func subsetNodes(a, b *pnode) bool {
+	switch {
+	case a == nil || a == b:
+		return true
+	case b == nil:
+		return false
+	case a.words != nil:
+		for i, w := range a.words {
+			if w&^b.words[i] != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	for i, c := range a.kids {
+		if !subsetNodes(c, b.kids[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Max returns the maximum element of the set;
+// it panics if the set is empty.
+func (s *PersistentSet) Max() int {
+	x := s.root
+	if x == nil {
+		panic("max not defined for empty set")
+	}
+	n := 0
+	for h := s.height; h > 0; h-- {
+		i := fanout - 1
+		for x.kids[i] == nil {
+			i--
+		}
+		n += i << childShift(h)
+		x = x.kids[i]
+	}
+	i := leafWords - 1
+	for x.words[i] == 0 {
+		i--
+	}
+	return n + i<<shift + len64(x.words[i]) - 1
+}
+
+// Min returns the minimum element of the set;
+// it panics if the set is empty.
+func (s *PersistentSet) Min() int {
+	if s.root == nil {
+		panic("min not defined for empty set")
+	}
+	return s.Next(-1)
+}
+
+// Size returns the number of elements in the set.
+func (s *PersistentSet) Size() int {
+	return sizeNode(s.root)
+}
+
+func sizeNode(x *pnode) int {
+	n := 0
+	switch {
+	case x == nil:
+	case x.words != nil:
+		for _, w := range x.words {
+			n += onesCount64(w)
+		}
+	default:
+		for _, c := range x.kids {
+			n += sizeNode(c)
+		}
+	}
+	return n
+}
+
+// Empty tells if the set is empty.
+func (s *PersistentSet) Empty() bool {
+	return s.root == nil
+}
+
+// Next returns the next element n, n > m, in the set,
+// or -1 if there is no such element.
+func (s *PersistentSet) Next(m int) int {
+	if m == MaxInt {
+		return -1
+	}
+	m = max(0, m+1) // Look for smallest element n ≥ m.
+	if !fits(m, s.height) {
+		return -1
+	}
+	return nextNode(s.root, s.height, 0, m)
+}
+
+// nextNode returns the smallest element n, n ≥ m, in x, a node at
+// height h whose first element is base, or -1 if there is no such element.
+func nextNode(x *pnode, h, base, m int) int {
+	if x == nil {
+		return -1
+	}
+	if h == 0 {
+		m -= base
+		i := m >> shift
+		t := uint(m & mask)
+		w := x.words[i] >> t << t // Zero out bits for numbers < m.
+		for w == 0 {
+			i++
+			if i == leafWords {
+				return -1
+			}
+			w = x.words[i]
+		}
+		return base + i<<shift + trailingZeros64(w)
+	}
+	cs := childShift(h)
+	for i := (m - base) >> cs; i < fanout && i <= MaxInt>>cs; i++ {
+		b := base + i<<cs
+		if n := nextNode(x.kids[i], h-1, b, max(m, b)); n != -1 {
+			return n
+		}
+	}
+	return -1
+}
+
+// Prev returns the previous element n, n < m, in the set,
+// or -1 if there is no such element.
+func (s *PersistentSet) Prev(m int) int {
+	if m <= 0 || s.root == nil {
+		return -1
+	}
+	m-- // Look for largest element n ≤ m.
+	if !fits(m, s.height) {
+		return s.Max()
+	}
+	return prevNode(s.root, s.height, 0, m)
+}
+
+// prevNode returns the largest element n, n ≤ m, in x, a node at
+// height h whose first element is base, or -1 if there is no such element.
+func prevNode(x *pnode, h, base, m int) int {
+	if x == nil {
+		return -1
+	}
+	if h == 0 {
+		m -= base
+		i := m >> shift
+		w := x.words[i] & bitMask(0, m&mask) // Zero out bits for numbers > m.
+		for w == 0 {
+			i--
+			if i < 0 {
+				return -1
+			}
+			w = x.words[i]
+		}
+		return base + i<<shift + len64(w) - 1
+	}
+	cs := childShift(h)
+	for i := (m - base) >> cs; i >= 0; i-- {
+		b := base + i<<cs
+		if n := prevNode(x.kids[i], h-1, b, min(m, b+(1<<cs-1))); n != -1 {
+			return n
+		}
+	}
+	return -1
+}
+
+// Visit calls the do function for each element of s in numerical order.
+// If do returns true, Visit returns immediately, skipping any remaining
+// elements, and returns true.
+func (s *PersistentSet) Visit(do func(n int) (skip bool)) (aborted bool) {
+	return visitNode(s.root, s.height, 0, do)
+}
+
+func visitNode(x *pnode, h, base int, do func(n int) (skip bool)) (aborted bool) {
+	switch {
+	case x == nil:
+	case h == 0:
+		for i, w := range x.words {
+			n := base + i<<shift
+			for w != 0 {
+				if do(n + trailingZeros64(w)) {
+					return true
+				}
+				w &= w - 1
+			}
+		}
+	default:
+		cs := childShift(h)
+		for i, c := range x.kids {
+			if visitNode(c, h-1, base+i<<cs, do) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the set. The elements
+// are listed in ascending order. Runs of at least three consecutive
+// elements from a to b are given as a..b.
+func (s *PersistentSet) String() string {
+	return formatSet(s.Visit)
+}
+
+// With returns a set that consists of the elements of s and n.
+// If n is negative, or already belongs to s, it returns s.
+func (s *PersistentSet) With(n int) *PersistentSet {
+	if n < 0 || s.Contains(n) {
+		return s
+	}
+	h := s.height
+	for !fits(n, h) {
+		h++
+	}
+	return &PersistentSet{root: withNode(lift(s.root, s.height, h), h, n), height: h}
+}
+
+// withNode returns a copy of x, a node at height h, with n added.
+func withNode(x *pnode, h, n int) *pnode {
+	y := new(pnode)
+	if h == 0 {
+		y.words = make([]uint64, leafWords)
+		if x != nil {
+			copy(y.words, x.words)
+		}
+		i := n & (1<<leafLog - 1)
+		y.words[i>>shift] |= 1 << uint(i&mask)
+		return y
+	}
+	y.kids = make([]*pnode, fanout)
+	if x != nil {
+		copy(y.kids, x.kids)
+	}
+	i := n >> childShift(h) & (fanout - 1)
+	y.kids[i] = withNode(y.kids[i], h-1, n)
+	return y
+}
+
+// Without returns a set that consists of the elements of s except n.
+// If n doesn't belong to s, it returns s.
+func (s *PersistentSet) Without(n int) *PersistentSet {
+	if !s.Contains(n) {
+		return s
+	}
+	return normalize(withoutNode(s.root, s.height, n), s.height)
+}
+
+// withoutNode returns a copy of x, a node at height h that contains n,
+// with n removed, or nil if the result is empty.
+func withoutNode(x *pnode, h, n int) *pnode {
+	y := new(pnode)
+	if h == 0 {
+		y.words = append([]uint64(nil), x.words...)
+		i := n & (1<<leafLog - 1)
+		y.words[i>>shift] &^= 1 << uint(i&mask)
+		return nonEmpty(y)
+	}
+	y.kids = append([]*pnode(nil), x.kids...)
+	i := n >> childShift(h) & (fanout - 1)
+	y.kids[i] = withoutNode(y.kids[i], h-1, n)
+	return nonEmpty(y)
+}
+
+// nonEmpty returns x, or nil if x is an empty node.
+func nonEmpty(x *pnode) *pnode {
+	for _, w := range x.words {
+		if w != 0 {
+			return x
+		}
+	}
+	for _, c := range x.kids {
+		if c != nil {
+			return x
+		}
+	}
+	return nil
+}
+
+// lift returns a node at height to ≥ from with the same elements as x,
+// a node at height from.
+func lift(x *pnode, from, to int) *pnode {
+	for ; x != nil && from < to; from++ {
+		y := &pnode{kids: make([]*pnode, fanout)}
+		y.kids[0] = x
+		x = y
+	}
+	return x
+}
+
+// normalize returns a set with root x, a node at height h,
+// and with as small height as possible.
+func normalize(x *pnode, h int) *PersistentSet {
+	for ; h > 0; h-- {
+		if x == nil {
+			h = 0
+			break
+		}
+		for _, c := range x.kids[1:] {
+			if c != nil {
+				return &PersistentSet{root: x, height: h}
+			}
+		}
+		x = x.kids[0]
+	}
+	return &PersistentSet{root: x, height: h}
+}
+
+// And returns a set that consists of all elements that belong
+// to both s1 and s2.
+func (s1 *PersistentSet) And(s2 *PersistentSet) *PersistentSet {
+	return s1.setOp(s2, func(a, b uint64) uint64 { return a & b })
+}
+
+// Or returns a set that contains all elements that belong
+// to either s1 or s2.
+func (s1 *PersistentSet) Or(s2 *PersistentSet) *PersistentSet {
+	return s1.setOp(s2, func(a, b uint64) uint64 { return a | b })
+}
+
+// Xor returns a set that contains all elements that belong
+// to either s1 or s2, but not to both.
+func (s1 *PersistentSet) Xor(s2 *PersistentSet) *PersistentSet {
+	return s1.setOp(s2, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// AndNot returns a set that consists of all elements that belong
+// to s1, but not to s2.
+func (s1 *PersistentSet) AndNot(s2 *PersistentSet) *PersistentSet {
+	return s1.setOp(s2, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// setOp returns the set whose words are op(a, b) for the words a of s1
+// and b of s2.
+func (s1 *PersistentSet) setOp(s2 *PersistentSet, op func(a, b uint64) uint64) *PersistentSet {
+	h := max(s1.height, s2.height)
+	a, b := lift(s1.root, s1.height, h), lift(s2.root, s2.height, h)
+	return normalize(opNode(a, b, h, op), h)
+}
+
+// opNode returns the node at height h whose words are op(a, b) for
+// the words a of x and b of y. The result shares as much as possible
+// with x and y.
+func opNode(x, y *pnode, h int, op func(a, b uint64) uint64) *pnode {
+	// The result is x, y or empty, if x and y are the same or one is empty.
+	pick := func(w uint64, z *pnode) *pnode {
+		if w == 0 {
+			return nil
+		}
+		return z
+	}
+	switch {
+	case x == y:
+		return pick(op(maxw, maxw), x)
+	case x == nil:
+		return pick(op(0, maxw), y)
+	case y == nil:
+		return pick(op(maxw, 0), x)
+	}
+	z := new(pnode)
+	if h == 0 {
+		z.words = make([]uint64, leafWords)
+		for i := range z.words {
+			z.words[i] = op(x.words[i], y.words[i])
+		}
+		switch {
+		case equalNodes(z, x):
+			return x
+		case equalNodes(z, y):
+			return y
+		}
+		return nonEmpty(z)
+	}
+	z.kids = make([]*pnode, fanout)
+	sameX, sameY := true, true
+	for i := range z.kids {
+		c := opNode(x.kids[i], y.kids[i], h-1, op)
+		z.kids[i] = c
+		sameX = sameX && c == x.kids[i]
+		sameY = sameY && c == y.kids[i]
+	}
+	switch {
+	case sameX:
+		return x
+	case sameY:
+		return y
+	}
+	return nonEmpty(z)
+}
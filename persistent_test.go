@@ -0,0 +1,165 @@
+package bit
+
+import (
+	"fmt"
+	"testing"
+)
+
+// CheckPersistentInvariants checks that the invariants for s hold.
+func CheckPersistentInvariants(t *testing.T, msg string, s *PersistentSet) {
+	var check func(x *pnode, h int)
+	check = func(x *pnode, h int) {
+		if x == nil {
+			return
+		}
+		if nonEmpty(x) == nil {
+			t.Errorf("Invariant for %s: empty node", msg)
+		}
+		if h == 0 {
+			if len(x.words) != leafWords || x.kids != nil {
+				t.Errorf("Invariant for %s: malformed leaf", msg)
+			}
+			return
+		}
+		if len(x.kids) != fanout || x.words != nil {
+			t.Errorf("Invariant for %s: malformed interior node", msg)
+			return
+		}
+		for _, c := range x.kids {
+			check(c, h-1)
+		}
+	}
+	check(s.root, s.height)
+	if s.root == nil && s.height != 0 {
+		t.Errorf("Invariant for %s: empty set of height %d", msg, s.height)
+	}
+	if s.root != nil && s.height > 0 && normalize(s.root, s.height).height != s.height {
+		t.Errorf("Invariant for %s: height %d not minimal", msg, s.height)
+	}
+}
+
+func TestPersistentSet(t *testing.T) {
+	const base = 1 << 30
+	for _, x := range []struct {
+		s      *PersistentSet
+		str    string
+		height int
+	}{
+		{NewPersistentSet(), "{}", 0},
+		{new(PersistentSet).With(-1), "{}", 0},
+		{NewPersistentSet(0, 1), "{0 1}", 0},
+		{NewPersistentSet(1, 2, 3).With(base), "{1..3 1073741824}", 5},
+		{NewPersistentSet(1, 2, 3).With(base).Without(base), "{1..3}", 0},
+		{NewPersistentSet(5000, 1).Without(5000).Without(1), "{}", 0},
+	} {
+		s := x.s
+		if str := s.String(); str != x.str {
+			t.Errorf("s.String() = %q; want %q", str, x.str)
+		}
+		if s.height != x.height {
+			t.Errorf("%v has height %d; want %d", s, s.height, x.height)
+		}
+		CheckPersistentInvariants(t, "PersistentSet", s)
+	}
+}
+
+func TestPersistentSetVersions(t *testing.T) {
+	// Build versions of a set, with and without sharing,
+	// and compare each version with a Set.
+	var versions []*PersistentSet
+	var sets []*Set
+	s, b := new(PersistentSet), new(Set)
+	x := 1
+	for i := 0; i < 2000; i++ {
+		x = (x*1103515245 + 12345) & 0x7fffffff
+		n := x % 100000
+		if i%3 == 0 {
+			s, b = s.Without(n>>1), b.Delete(n>>1)
+		} else {
+			s, b = s.With(n), b.Add(n)
+		}
+		if i%100 == 99 {
+			versions = append(versions, s)
+			sets = append(sets, new(Set).Set(b))
+		}
+	}
+	for i, s := range versions {
+		b := sets[i]
+		CheckReadSet(t, fmt.Sprintf("version %d", i), s, b)
+		if !s.Mutable().Equal(b) {
+			t.Errorf("%v.Mutable() = %v; want %v", s, s.Mutable(), b)
+		}
+		if !s.Equal(b.Persistent()) {
+			t.Errorf("%v.Equal(%v.Persistent()) = false; want true", s, b)
+		}
+		CheckPersistentInvariants(t, "With/Without", s)
+		CheckInvariants(t, "Mutable", s.Mutable())
+	}
+
+	// Unchanged versions are shared.
+	s = versions[len(versions)-1]
+	n := s.Max()
+	if s.With(n) != s || s.Without(n+1) != s || s.Or(s).root != s.root || s.And(s).root != s.root {
+		t.Errorf("unchanged set not shared")
+	}
+	if t1 := s.With(n + 1); t1.root.kids[0] != s.root.kids[0] {
+		t.Errorf("With doesn't share unchanged blocks")
+	}
+}
+
+func TestPersistentSetLarge(t *testing.T) {
+	s := NewPersistentSet(1, 100).With(MaxInt)
+	CheckPersistentInvariants(t, "With(MaxInt)", s)
+	if !s.Contains(MaxInt) || s.Contains(MaxInt-1) {
+		t.Errorf("%v.Contains(MaxInt) gives wrong result", s)
+	}
+	if max := s.Max(); max != MaxInt {
+		t.Errorf("%v.Max() = %d; want %d", s, max, MaxInt)
+	}
+	for _, x := range []struct {
+		m, next, prev int
+	}{
+		{MinInt, 1, -1},
+		{1, 100, -1},
+		{100, MaxInt, 1},
+		{MaxInt - 1, MaxInt, 100},
+		{MaxInt, -1, 100},
+	} {
+		if n := s.Next(x.m); n != x.next {
+			t.Errorf("%v.Next(%d) = %d; want %d", s, x.m, n, x.next)
+		}
+		if n := s.Prev(x.m); n != x.prev {
+			t.Errorf("%v.Prev(%d) = %d; want %d", s, x.m, n, x.prev)
+		}
+	}
+	if s = s.Without(MaxInt); s.height != 0 || s.String() != "{1 100}" {
+		t.Errorf("Without(MaxInt) = %v of height %d; want {1 100} of height 0", s, s.height)
+	}
+}
+
+func TestPersistentSetBinOp(t *testing.T) {
+	var sets []readSet
+	for _, s := range []*Set{
+		New(),
+		New(1, 2),
+		New(2, 3, 1000),
+		new(Set).AddRange(600, 5000),
+		new(Set).AddRange(1, 200000).Delete(1500),
+		BuildTestSet(1000),
+		New(1 << 20),
+	} {
+		sets = append(sets, s.Persistent())
+	}
+	CheckBinOps(t, sets, func(a, b readSet) []readSet {
+		x, y := a.(*PersistentSet), b.(*PersistentSet)
+		res := []readSet{x.And(y), x.Or(y), x.Xor(y), x.AndNot(y)}
+		for _, r := range res {
+			CheckPersistentInvariants(t, "BinOp", r.(*PersistentSet))
+		}
+		return res
+	}, func(a, b readSet) bool {
+		return a.(*PersistentSet).Equal(b.(*PersistentSet))
+	}, func(a, b readSet) bool {
+		return a.(*PersistentSet).Subset(b.(*PersistentSet))
+	})
+}
@@ -0,0 +1,91 @@
+package bit
+
+// VisitRanges calls the do function for each maximal range [lo, hi)
+// of consecutive elements in s, in numerical order. If do returns true,
+// VisitRanges returns immediately, skipping any remaining ranges,
+// and returns true. The behavior of VisitRanges is undefined
+// if do changes the set.
+func (s *Set) VisitRanges(do func(lo, hi int) (skip bool)) (aborted bool) {
+	return visitRuns(s.data, do)
+}
+
+// Ranges returns the maximal ranges [lo, hi) of consecutive elements
+// in s, in numerical order.
+func (s *Set) Ranges() [][2]int {
+	var res [][2]int
+	s.VisitRanges(func(lo, hi int) (skip bool) {
+		res = append(res, [2]int{lo, hi})
+		return
+	})
+	return res
+}
+
+// visitRuns calls the do function for each maximal run [lo, hi)
+// of nonzero bits in d, in order, where bit n is bit n&mask of d[n>>shift].
+// If do returns true, visitRuns returns immediately and returns true.
+func visitRuns(d []uint64, do func(lo, hi int) (skip bool)) (aborted bool) {
+	lo := -1 // Start of current run, or -1 if not in a run.
+	for i, w := range d {
+		base := i << shift
+		for j := 0; ; { // Bit 0 of w is bit j of d[i].
+			if lo < 0 {
+				if w == 0 {
+					break
+				}
+				b := trailingZeros64(w)
+				j += b
+				w >>= uint(b)
+				lo = base + j
+			}
+			b := trailingZeros64(^w)
+			j += b
+			if j == bpw {
+				break // Run continues in next word.
+			}
+			if do(lo, base+j) {
+				return true
+			}
+			lo = -1
+			w >>= uint(b)
+		}
+	}
+	if lo >= 0 { // Run extends to the end of d.
+		return do(lo, len(d)<<shift)
+	}
+	return false
+}
+
+// FindRun returns the smallest n, n ≥ from, such that all k integers
+// from n to n+k-1 belong to s, or -1 if there is no such n.
+// If k ≤ 0, it returns max(0, from).
+func (s *Set) FindRun(k, from int) int {
+	from = max(0, from)
+	if k <= 0 {
+		return from
+	}
+	for n := s.Next(from - 1); n != -1; {
+		end := s.NextAbsent(n) // Runs are skipped a word at a time.
+		if end-n >= k {
+			return n
+		}
+		n = s.Next(end)
+	}
+	return -1
+}
+
+// FindGap returns the smallest n, n ≥ from, such that none of the k
+// integers from n to n+k-1 belong to s.
+// If k ≤ 0, it returns max(0, from).
+func (s *Set) FindGap(k, from int) int {
+	from = max(0, from)
+	if k <= 0 {
+		return from
+	}
+	for n := s.NextAbsent(from - 1); ; {
+		end := s.Next(n) // Gaps are skipped a word at a time.
+		if end == -1 || end-n >= k {
+			return n
+		}
+		n = s.NextAbsent(end)
+	}
+}
@@ -0,0 +1,109 @@
+package bit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRanges(t *testing.T) {
+	for _, x := range []struct {
+		s   *Set
+		res [][2]int
+	}{
+		{New(), nil},
+		{New(0), [][2]int{{0, 1}}},
+		{New(1, 2, 3, 5), [][2]int{{1, 4}, {5, 6}}},
+		{New(63, 64), [][2]int{{63, 65}}},
+		{New().AddRange(0, 64), [][2]int{{0, 64}}},
+		{New().AddRange(0, 128).Add(129), [][2]int{{0, 128}, {129, 130}}},
+		{New().AddRange(10, 1000).AddRange(1001, 1002), [][2]int{{10, 1000}, {1001, 1002}}},
+		{New(100, 200, 300), [][2]int{{100, 101}, {200, 201}, {300, 301}}},
+	} {
+		res := x.s.Ranges()
+		if !reflect.DeepEqual(res, x.res) {
+			t.Errorf("%v.Ranges() = %v; want %v", x.s, res, x.res)
+		}
+	}
+
+	// Compare with ranges found by Visit.
+	s := BuildTestSet(1000).AddRange(5000, 6000)
+	var exp [][2]int
+	s.Visit(func(n int) (skip bool) {
+		if k := len(exp) - 1; k >= 0 && exp[k][1] == n {
+			exp[k][1]++
+		} else {
+			exp = append(exp, [2]int{n, n + 1})
+		}
+		return
+	})
+	if res := s.Ranges(); !reflect.DeepEqual(res, exp) {
+		t.Errorf("%v.Ranges() = %v; want %v", s, res, exp)
+	}
+}
+
+func TestVisitRanges(t *testing.T) {
+	s := New(1, 2, 3, 5, 100)
+	count := 0
+	aborted := s.VisitRanges(func(lo, hi int) (skip bool) {
+		count++
+		return lo == 5
+	})
+	if !aborted || count != 2 {
+		t.Errorf("VisitRanges aborted = %t after %d calls; want true after 2", aborted, count)
+	}
+	count = 0
+	aborted = s.VisitRanges(func(lo, hi int) (skip bool) {
+		count++
+		return
+	})
+	if aborted || count != 3 {
+		t.Errorf("VisitRanges aborted = %t after %d calls; want false after 3", aborted, count)
+	}
+}
+
+func TestFindRunGap(t *testing.T) {
+	for _, s := range []*Set{
+		New(),
+		New(0),
+		New(1, 2, 3, 5),
+		New().AddRange(0, 64).AddRange(65, 200),
+		New().AddRange(10, 20).AddRange(50, 150).AddRange(300, 310),
+		BuildTestSet(200),
+	} {
+		for _, k := range []int{1, 2, 3, 5, 10, 64, 100, 200} {
+			for _, from := range []int{-1, 0, 1, 5, 20, 63, 64, 100, 300} {
+				// Brute force.
+				run, gap := -1, -1
+				for n := max(0, from); n < 2000 && (run == -1 || gap == -1); n++ {
+					allIn, allOut := true, true
+					for i := n; i < n+k; i++ {
+						if s.Contains(i) {
+							allOut = false
+						} else {
+							allIn = false
+						}
+					}
+					if allIn && run == -1 {
+						run = n
+					}
+					if allOut && gap == -1 {
+						gap = n
+					}
+				}
+				if res := s.FindRun(k, from); res != run {
+					t.Errorf("%v.FindRun(%d, %d) = %d; want %d", s, k, from, res, run)
+				}
+				if res := s.FindGap(k, from); res != gap {
+					t.Errorf("%v.FindGap(%d, %d) = %d; want %d", s, k, from, res, gap)
+				}
+			}
+		}
+	}
+	s := New(1, 2, 3)
+	if res := s.FindRun(0, 5); res != 5 {
+		t.Errorf("%v.FindRun(0, 5) = %d; want 5", s, res)
+	}
+	if res := s.FindGap(-1, -5); res != 0 {
+		t.Errorf("%v.FindGap(-1, -5) = %d; want 0", s, res)
+	}
+}
@@ -0,0 +1,139 @@
+package bit
+
+// Min returns the minimum element of the set;
+// it panics if the set is empty.
+func (s *Set) Min() int {
+	if len(s.data) == 0 {
+		panic("min not defined for empty set")
+	}
+	d := s.data
+	i := 0
+	for d[i] == 0 {
+		i++
+	}
+	return i<<shift + trailingZeros64(d[i])
+}
+
+// Rank returns the number of elements e, e < n, in the set.
+func (s *Set) Rank(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	d := s.data
+	i := n >> shift
+	if i >= len(d) {
+		return s.Size()
+	}
+	r := 0
+	for _, w := range d[:i] {
+		r += onesCount64(w)
+	}
+	return r + rankWord(d[i], n&mask)
+}
+
+// Select returns the element e of the set such that Rank(e) == k,
+// i.e. the k-th smallest element counting from zero,
+// or -1 if there is no such element.
+func (s *Set) Select(k int) int {
+	if k < 0 {
+		return -1
+	}
+	for i, w := range s.data {
+		c := onesCount64(w)
+		if k < c {
+			return i<<shift + selectWord(w, k)
+		}
+		k -= c
+	}
+	return -1
+}
+
+// blockWords is the number of words per block in a RankIndex.
+const blockWords = 8
+
+// A RankIndex holds precomputed element counts for a set, which
+// speeds up the Rank and Select queries. The index is only valid
+// as long as the set isn't changed.
+type RankIndex struct {
+	s     *Set
+	count []int // count[j] is the number of elements in words [0, j*blockWords)
+}
+
+// RankIndex computes a rank index for s. It uses about one int
+// per 512 possible elements of s.
+func (s *Set) RankIndex() *RankIndex {
+	d := s.data
+	count := make([]int, (len(d)+blockWords-1)/blockWords+1)
+	c := 0
+	for i, w := range d {
+		if i%blockWords == 0 {
+			count[i/blockWords] = c
+		}
+		c += onesCount64(w)
+	}
+	count[len(count)-1] = c
+	return &RankIndex{s: s, count: count}
+}
+
+// Rank returns the number of elements e, e < n, in the set.
+func (x *RankIndex) Rank(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	d := x.s.data
+	i := n >> shift
+	if i >= len(d) {
+		return x.count[len(x.count)-1]
+	}
+	j := i / blockWords
+	r := x.count[j]
+	for _, w := range d[j*blockWords : i] {
+		r += onesCount64(w)
+	}
+	return r + rankWord(d[i], n&mask)
+}
+
+// Select returns the element e of the set such that Rank(e) == k,
+// or -1 if there is no such element.
+func (x *RankIndex) Select(k int) int {
+	count := x.count
+	if k < 0 || k >= count[len(count)-1] {
+		return -1
+	}
+	// Binary search for the last block j with count[j] ≤ k.
+	lo, hi := 0, len(count)-1
+	for hi-lo > 1 {
+		m := int(uint(lo+hi) >> 1)
+		if count[m] <= k {
+			lo = m
+		} else {
+			hi = m
+		}
+	}
+	k -= count[lo]
+	d := x.s.data
+	for i := lo * blockWords; ; i++ {
+		c := onesCount64(d[i])
+		if k < c {
+			return i<<shift + selectWord(d[i], k)
+		}
+		k -= c
+	}
+}
+
+// rankWord returns the number of nonzero bits in w at positions less than n.
+func rankWord(w uint64, n int) int {
+	if n == 0 {
+		return 0
+	}
+	return onesCount64(w & bitMask(0, n-1))
+}
+
+// selectWord returns the position of the nonzero bit in w
+// that has k nonzero bits below it, where k < onesCount64(w).
+func selectWord(w uint64, k int) int {
+	for ; k > 0; k-- {
+		w &= w - 1 // Clear lowest nonzero bit.
+	}
+	return trailingZeros64(w)
+}
@@ -0,0 +1,83 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestMin(t *testing.T) {
+	for _, x := range []struct {
+		s   *Set
+		min int
+	}{
+		{New(0), 0},
+		{New(65), 65},
+		{New(1, 2, 3), 1},
+		{New(100, 200, 300), 100},
+	} {
+		s := x.s
+		min := s.Min()
+		if min != x.min {
+			t.Errorf("%v.Min() = %d; want %d", s, min, x.min)
+		}
+	}
+
+	s := New()
+	if !Panics((*Set).Min, s) {
+		t.Errorf("Min() should panic for empty set.")
+	}
+}
+
+func TestRankSelect(t *testing.T) {
+	for _, s := range []*Set{
+		New(),
+		New(0),
+		New(1, 2, 3),
+		New(63, 64),
+		New(100, 200, 300),
+		New().AddRange(0, 1000),
+		BuildTestSet(1000).AddRange(10000, 11000),
+	} {
+		x := s.RankIndex()
+		size := s.Size()
+		max := -1
+		if size > 0 {
+			max = s.Max()
+		}
+		// Rank counts the elements below n.
+		rank := 0
+		for n := -2; n <= max+2; n++ {
+			if r := s.Rank(n); r != rank {
+				t.Errorf("%v.Rank(%d) = %d; want %d", s, n, r, rank)
+			}
+			if r := x.Rank(n); r != rank {
+				t.Errorf("%v.RankIndex().Rank(%d) = %d; want %d", s, n, r, rank)
+			}
+			if s.Contains(n) {
+				rank++
+			}
+		}
+		if r := x.Rank(MaxInt); r != size {
+			t.Errorf("%v.RankIndex().Rank(MaxInt) = %d; want %d", s, r, size)
+		}
+		// Select inverts Rank.
+		k := 0
+		s.Visit(func(n int) (skip bool) {
+			if e := s.Select(k); e != n {
+				t.Errorf("%v.Select(%d) = %d; want %d", s, k, e, n)
+			}
+			if e := x.Select(k); e != n {
+				t.Errorf("%v.RankIndex().Select(%d) = %d; want %d", s, k, e, n)
+			}
+			k++
+			return
+		})
+		for _, k := range []int{MinInt, -1, size, size + 1} {
+			if e := s.Select(k); e != -1 {
+				t.Errorf("%v.Select(%d) = %d; want -1", s, k, e)
+			}
+			if e := x.Select(k); e != -1 {
+				t.Errorf("%v.RankIndex().Select(%d) = %d; want -1", s, k, e)
+			}
+		}
+	}
+}
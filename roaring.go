@@ -0,0 +1,289 @@
+package bit
+
+import (
+	"encoding/binary"
+)
+
+// Constants from the Roaring portable serialization format,
+// https://github.com/RoaringBitmap/RoaringFormatSpec.
+const (
+	roaringCookieNoRun  = 12346 // no run containers; container count follows
+	roaringCookie       = 12347 // count-1 in high 16 bits; run flags follow
+	roaringNoOffsetMax  = 4     // with roaringCookie, offsets only if count ≥ 4
+	roaringMaxArray     = 4096  // maximum cardinality of an array container
+	roaringBitmapWords  = 1 << 16 / bpw
+	roaringBitmapBytes  = 8 * roaringBitmapWords
+	roaringMaxDataWords = 1 << 32 / bpw // sets of uint32 values
+)
+
+// Container types.
+const (
+	roaringArray = iota
+	roaringBitmap
+	roaringRun
+)
+
+// roaringContainer describes a nonempty chunk of 2^16 possible elements
+// starting at key<<16.
+type roaringContainer struct {
+	key  int
+	card int // cardinality
+	runs int // number of runs of consecutive elements
+	typ  int
+}
+
+// size returns the number of bytes in the serialized container.
+func (c *roaringContainer) size() int {
+	switch c.typ {
+	case roaringArray:
+		return 2 * c.card
+	case roaringBitmap:
+		return roaringBitmapBytes
+	}
+	return 2 + 4*c.runs
+}
+
+// MarshalRoaring returns an encoding of s in the Roaring portable
+// serialization format, which is used by the Roaring bitmap libraries
+// for Java, C, Go and other languages. The format only supports
+// elements that fit in a uint32; MarshalRoaring returns a *FormatError
+// if s contains a larger element.
+func (s *Set) MarshalRoaring() ([]byte, error) {
+	d := s.data
+	if len(d) > roaringMaxDataWords {
+		return nil, roaringError("element out of range")
+	}
+
+	// Compute container types.
+	var cs []roaringContainer
+	hasRuns := false
+	for i := 0; i < len(d); i += roaringBitmapWords {
+		chunk := d[i:min(len(d), i+roaringBitmapWords)]
+		c := roaringContainer{key: i / roaringBitmapWords}
+		var prev uint64 // Previous word in chunk.
+		for _, w := range chunk {
+			c.card += onesCount64(w)
+			c.runs += onesCount64(w &^ (w<<1 | prev>>(bpw-1))) // Count run starts.
+			prev = w
+		}
+		if c.card == 0 {
+			continue
+		}
+		if c.card > roaringMaxArray {
+			c.typ = roaringBitmap
+		}
+		if size := 2 + 4*c.runs; size < c.size() {
+			c.typ = roaringRun
+			hasRuns = true
+		}
+		cs = append(cs, c)
+	}
+
+	// Header.
+	n := len(cs)
+	var buf []byte
+	withOffsets := true
+	if hasRuns {
+		buf = appendUint32(buf, uint32(roaringCookie|(n-1)<<16))
+		flags := make([]byte, (n+7)/8)
+		for i, c := range cs {
+			if c.typ == roaringRun {
+				flags[i/8] |= 1 << uint(i%8)
+			}
+		}
+		buf = append(buf, flags...)
+		withOffsets = n >= roaringNoOffsetMax
+	} else {
+		buf = appendUint32(buf, roaringCookieNoRun)
+		buf = appendUint32(buf, uint32(n))
+	}
+	for _, c := range cs {
+		buf = appendUint16(buf, uint16(c.key))
+		buf = appendUint16(buf, uint16(c.card-1))
+	}
+	if withOffsets {
+		offset := len(buf) + 4*n
+		for _, c := range cs {
+			buf = appendUint32(buf, uint32(offset))
+			offset += c.size()
+		}
+	}
+
+	// Containers.
+	for _, c := range cs {
+		chunk := d[c.key*roaringBitmapWords : min(len(d), (c.key+1)*roaringBitmapWords)]
+		switch c.typ {
+		case roaringArray:
+			for i, w := range chunk {
+				for w != 0 {
+					b := trailingZeros64(w)
+					buf = appendUint16(buf, uint16(i<<shift+b))
+					w &= w - 1
+				}
+			}
+		case roaringBitmap:
+			for _, w := range chunk {
+				buf = appendUint64(buf, w)
+			}
+			for i := len(chunk); i < roaringBitmapWords; i++ {
+				buf = appendUint64(buf, 0)
+			}
+		case roaringRun:
+			buf = appendUint16(buf, uint16(c.runs))
+			visitRuns(chunk, func(lo, hi int) (skip bool) {
+				buf = appendUint16(buf, uint16(lo))
+				buf = appendUint16(buf, uint16(hi-1-lo))
+				return
+			})
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalRoaring sets s to the set encoded in data, which should be
+// in the Roaring portable serialization format. If data is not a valid
+// encoding, it returns a *FormatError and leaves s unchanged.
+func (s *Set) UnmarshalRoaring(data []byte) error {
+	r := &roaringReader{data: data}
+	cookie := r.uint32()
+	var n int
+	var isRun []byte // Run container flags.
+	withOffsets := true
+	switch {
+	case r.err != nil:
+	case cookie == roaringCookieNoRun:
+		n = int(r.uint32())
+	case cookie&0xffff == roaringCookie:
+		n = int(cookie>>16) + 1
+		isRun = r.bytes((n + 7) / 8)
+		withOffsets = n >= roaringNoOffsetMax
+	default:
+		return roaringError("unknown cookie")
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if n > 1<<16 {
+		return roaringError("too many containers")
+	}
+
+	cs := make([]roaringContainer, n)
+	for i := range cs {
+		c := &cs[i]
+		c.key = int(r.uint16())
+		c.card = int(r.uint16()) + 1
+		switch {
+		case isRun != nil && isRun[i/8]&(1<<uint(i%8)) != 0:
+			c.typ = roaringRun
+		case c.card > roaringMaxArray:
+			c.typ = roaringBitmap
+		}
+		if i > 0 && c.key <= cs[i-1].key {
+			return roaringError("container keys not in increasing order")
+		}
+	}
+	if withOffsets {
+		r.bytes(4 * n) // Offsets are not needed when reading sequentially.
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	res := new(Set)
+	if n > 0 {
+		res.realloc((cs[n-1].key + 1) * roaringBitmapWords)
+	}
+	d := res.data
+	for i := range cs {
+		c := &cs[i]
+		base := c.key << 16
+		chunk := d[c.key*roaringBitmapWords : (c.key+1)*roaringBitmapWords]
+		switch c.typ {
+		case roaringArray:
+			for j := 0; j < c.card; j++ {
+				e := int(r.uint16())
+				chunk[e>>shift] |= 1 << uint(e&mask)
+			}
+		case roaringBitmap:
+			card := 0
+			for j := range chunk {
+				w := r.uint64()
+				chunk[j] = w
+				card += onesCount64(w)
+			}
+			if r.err == nil && card != c.card {
+				return roaringError("bitmap cardinality mismatch")
+			}
+		case roaringRun:
+			runs := int(r.uint16())
+			for j := 0; j < runs && r.err == nil; j++ {
+				start, length := int(r.uint16()), int(r.uint16())+1
+				if start+length > 1<<16 {
+					return roaringError("run out of range")
+				}
+				res.AddRange(base+start, base+start+length)
+			}
+		}
+		if r.err != nil {
+			return r.err
+		}
+	}
+	res.trim()
+	s.data = res.data
+	return nil
+}
+
+// roaringReader reads little-endian integers from data.
+// After an error, all reads return zero values and err is set.
+type roaringReader struct {
+	data []byte
+	err  error
+}
+
+// bytes returns the next n bytes.
+func (r *roaringReader) bytes(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		r.err = roaringError("unexpected end of data")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *roaringReader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *roaringReader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *roaringReader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v), byte(v>>8))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(v)), uint32(v>>32))
+}
+
+func roaringError(msg string) error {
+	return &FormatError{Format: "Roaring", Msg: msg}
+}
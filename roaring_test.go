@@ -0,0 +1,87 @@
+package bit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRoaring(t *testing.T) {
+	for _, x := range []struct {
+		s    *Set
+		data []byte
+	}{
+		{New(), []byte{0x3a, 0x30, 0, 0, 0, 0, 0, 0}},
+		{New(1, 2, 3), []byte{
+			0x3a, 0x30, 0, 0, 1, 0, 0, 0, // cookie, count
+			0, 0, 2, 0, // key, cardinality-1
+			16, 0, 0, 0, // offset
+			1, 0, 2, 0, 3, 0, // array container
+		}},
+		{New().AddRange(0, 10), []byte{
+			0x3b, 0x30, 0, 0, 1, // cookie, run flags
+			0, 0, 9, 0, // key, cardinality-1
+			1, 0, 0, 0, 9, 0, // run container
+		}},
+		{New(1<<16+1).AddRange(1<<17-10, 1<<17+10), []byte{
+			0x3b, 0x30, 1, 0, 3, // cookie, run flags
+			1, 0, 10, 0, 2, 0, 9, 0, // keys, cardinalities-1
+			2, 0, 1, 0, 0, 0, 0xf6, 0xff, 9, 0, // run container
+			1, 0, 0, 0, 9, 0, // run container
+		}},
+	} {
+		s := x.s
+		data, err := s.MarshalRoaring()
+		if err != nil || !bytes.Equal(data, x.data) {
+			t.Errorf("%v.MarshalRoaring() = %v, %v; want %v, nil", s, data, err, x.data)
+		}
+		res := New(5, 500)
+		if err := res.UnmarshalRoaring(x.data); err != nil || !res.Equal(s) {
+			t.Errorf("UnmarshalRoaring(%v) = %v, %v; want %v, nil", x.data, res, err, s)
+		}
+	}
+
+	for _, s := range []*Set{
+		New(0),
+		New(1<<24 - 1),
+		New(1, 100, 1000, 1<<16, 1<<20),
+		New().AddRange(60, 70).AddRange(120, 200).Add(1<<16 - 1),
+		New().AddRange(0, 1<<16).AddRange(1<<17, 1<<18),
+		New().AddRange(1<<16-64, 1<<16).AddRange(1<<16+1, 1<<16+65),
+		BuildTestSet(1 << 14),
+		BuildTestSet(1<<10).AddRange(1<<17, 1<<18),
+		BuildTestSet(1 << 8).Add(4 << 16).Add(8 << 16).Add(9 << 16).Add(12<<16 + 3),
+	} {
+		data, err := s.MarshalRoaring()
+		if err != nil {
+			t.Errorf("%v.MarshalRoaring() returned error %v", s, err)
+			continue
+		}
+		res := New(5, 500)
+		if err := res.UnmarshalRoaring(data); err != nil || !res.Equal(s) {
+			t.Errorf("UnmarshalRoaring(%v.MarshalRoaring()) = %v, %v", s, res, err)
+		}
+		CheckInvariants(t, "UnmarshalRoaring", res)
+	}
+}
+
+func TestRoaringError(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		{0x3a, 0x30, 0, 0},
+		{0x3a, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, 2, 0, 16, 0, 0, 0, 1, 0, 2, 0},
+		{0x3c, 0x30, 0, 0, 0, 0, 0, 0},
+		{0x3b, 0x30, 0, 0},
+		{0x3b, 0x30, 0, 0, 1, 0, 0, 9, 0, 1, 0, 0xff, 0xff, 9, 0},
+		{0x3b, 0x30, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{0x3a, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0x10, 16, 0, 0, 0, 0xff},
+	} {
+		s := New(1, 2, 3)
+		err := s.UnmarshalRoaring(data)
+		if _, ok := err.(*FormatError); !ok {
+			t.Errorf("UnmarshalRoaring(%v) = %v; want *FormatError", data, err)
+		}
+		if !s.Equal(New(1, 2, 3)) {
+			t.Errorf("UnmarshalRoaring(%v) changed set to %v", data, s)
+		}
+	}
+}
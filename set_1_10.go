@@ -17,8 +17,8 @@
 // Primes contains a short and simple, but still efficient,
 // implementation of a prime number sieve.
 //
-// Union is a more advanced example demonstrating how to build
-// an efficient variadic Union function using the SetOr method.
+// Union shows how to combine any number of sets in a single pass
+// with the Union, Intersection and AtLeast functions.
 //
 package bit
 
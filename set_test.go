@@ -1,6 +1,7 @@
 package bit
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 	"testing"
@@ -50,6 +51,104 @@ func call(fv reflect.Value, pv []reflect.Value) (b bool) {
 	return
 }
 
+// A readSet is a set type with the same read methods as Set.
+type readSet interface {
+	Contains(n int) bool
+	Max() int
+	Min() int
+	Size() int
+	Empty() bool
+	Next(m int) int
+	Prev(m int) int
+	Visit(do func(n int) (skip bool)) (aborted bool)
+	String() string
+}
+
+// visitSet returns a Set with the elements visited by visit.
+func visitSet(visit func(do func(n int) (skip bool)) (aborted bool)) *Set {
+	res := new(Set)
+	visit(func(n int) (skip bool) {
+		res.Add(n)
+		return
+	})
+	return res
+}
+
+// CheckReadSet checks that the read methods of s give the same results
+// as those of exp. Next, Prev and Contains are checked at the ends of
+// each run of elements in exp and at the extreme values.
+func CheckReadSet(t *testing.T, msg string, s readSet, exp *Set) {
+	if res := visitSet(s.Visit); !res.Equal(exp) {
+		t.Errorf("%s: Visit gives %v; want %v", msg, res, exp)
+		return
+	}
+	if str := s.String(); str != exp.String() {
+		t.Errorf("%s: String() = %q; want %q", msg, str, exp.String())
+	}
+	if size := s.Size(); size != exp.Size() {
+		t.Errorf("%s: Size() = %d; want %d", msg, size, exp.Size())
+	}
+	if empty := s.Empty(); empty != exp.Empty() {
+		t.Errorf("%s: Empty() = %t; want %t", msg, empty, !empty)
+	}
+	if exp.Empty() {
+		if !Panics(s.Max) || !Panics(s.Min) {
+			t.Errorf("%s: Max() and Min() should panic for empty set.", msg)
+		}
+	} else if min, max := s.Min(), s.Max(); min != exp.Min() || max != exp.Max() {
+		t.Errorf("%s: Min(), Max() = %d, %d; want %d, %d", msg, min, max, exp.Min(), exp.Max())
+	}
+	probes := []int{MinInt, -1, 0, MaxInt}
+	exp.Visit(func(n int) (skip bool) {
+		if !exp.Contains(n-1) || !exp.Contains(n+1) {
+			probes = append(probes, n-1, n, n+1)
+		}
+		return
+	})
+	for _, m := range probes {
+		if c := s.Contains(m); c != exp.Contains(m) {
+			t.Errorf("%s: Contains(%d) = %t; want %t", msg, m, c, !c)
+		}
+		if n := s.Next(m); n != exp.Next(m) {
+			t.Errorf("%s: Next(%d) = %d; want %d", msg, m, n, exp.Next(m))
+		}
+		if n := s.Prev(m); n != exp.Prev(m) {
+			t.Errorf("%s: Prev(%d) = %d; want %d", msg, m, n, exp.Prev(m))
+		}
+	}
+}
+
+// CheckBinOps checks the binary operations of a set type against those
+// of Set for all pairs of sets. The ops function returns the results of
+// a.And(b), a.Or(b), a.Xor(b) and a.AndNot(b), and equal and subset
+// return a.Equal(b) and a.Subset(b). A nil subset is skipped.
+func CheckBinOps(t *testing.T, sets []readSet, ops func(a, b readSet) []readSet, equal, subset func(a, b readSet) bool) {
+	bs := make([]*Set, len(sets))
+	for i, a := range sets {
+		bs[i] = visitSet(a.Visit)
+		CheckReadSet(t, a.String(), a, bs[i])
+	}
+	for i, a := range sets {
+		for j, b := range sets {
+			sa, sb := bs[i], bs[j]
+			exp := []*Set{sa.And(sb), sa.Or(sb), sa.Xor(sb), sa.AndNot(sb)}
+			for i, res := range ops(a, b) {
+				name := []string{"And", "Or", "Xor", "AndNot"}[i]
+				CheckReadSet(t, fmt.Sprintf("%v.%s(%v)", a, name, b), res, exp[i])
+			}
+			if eq := equal(a, b); eq != sa.Equal(sb) {
+				t.Errorf("%v.Equal(%v) = %t; want %t", a, b, eq, !eq)
+			}
+			if subset == nil {
+				continue
+			}
+			if sub := subset(a, b); sub != sa.Subset(sb) {
+				t.Errorf("%v.Subset(%v) = %t; want %t", a, b, sub, !sub)
+			}
+		}
+	}
+}
+
 func TestNew(t *testing.T) {
 	for _, s := range []*Set{
 		New(),
@@ -404,6 +503,7 @@ func TestRange(t *testing.T) {
 	rangeFuncs := []rangeFunc{
 		{(*Set).Add, (*Set).AddRange, "AddRange"},
 		{(*Set).Delete, (*Set).DeleteRange, "DeleteRange"},
+		{(*Set).Flip, (*Set).FlipRange, "FlipRange"},
 	}
 
 	for _, x := range []struct {
@@ -0,0 +1,86 @@
+package bit
+
+// ShiftUp adds k to all elements n, n ≥ from, in s and returns
+// a pointer to the updated set. This opens a gap of k integers,
+// from from to from+k-1, that do not belong to the set.
+// If k ≤ 0, the set is not changed.
+func (s *Set) ShiftUp(from, k int) *Set {
+	from = max(0, from)
+	fw := from >> shift
+	if k <= 0 || fw >= len(s.data) {
+		return s
+	}
+	l := len(s.data)
+	s.resize((l<<shift-1+k)>>shift + 1)
+	d := s.data
+	low := d[fw] & bitMask0(from&mask) // Elements below from are kept.
+	d[fw] &^= low
+	ws, bs := k>>shift, uint(k&mask)
+	for j := len(d) - 1; j >= fw; j-- {
+		var w uint64
+		if a := j - ws; a >= fw {
+			w = d[a] << bs
+			if a > fw {
+				w |= d[a-1] >> (bpw - bs)
+			}
+		}
+		d[j] = w
+	}
+	d[fw] |= low
+	s.trim()
+	return s
+}
+
+// ShiftDown removes all integers from from to from+k-1 from s,
+// subtracts k from all elements n, n ≥ from+k, and returns a pointer
+// to the updated set. If k ≤ 0, the set is not changed.
+func (s *Set) ShiftDown(from, k int) *Set {
+	from = max(0, from)
+	if k <= 0 {
+		return s
+	}
+	if k > MaxInt-from { // All elements n, n ≥ from, are removed.
+		return s.DeleteRange(from, MaxInt)
+	}
+	s.DeleteRange(from, from+k)
+	d := s.data
+	fw := from >> shift
+	if fw >= len(d) {
+		return s
+	}
+	low := d[fw] & bitMask0(from&mask) // Elements below from are kept.
+	d[fw] &^= low
+	ws, bs := k>>shift, uint(k&mask)
+	l := len(d)
+	for j := fw; j < l; j++ {
+		var w uint64
+		if a := j + ws; a < l {
+			w = d[a] >> bs
+			if a+1 < l {
+				w |= d[a+1] << (bpw - bs)
+			}
+		}
+		d[j] = w
+	}
+	d[fw] |= low
+	s.trim()
+	return s
+}
+
+// Lsh adds k to all elements of s and returns a pointer to the updated set.
+// If k ≤ 0, the set is not changed.
+func (s *Set) Lsh(k int) *Set {
+	return s.ShiftUp(0, k)
+}
+
+// Rsh removes all elements n, n < k, from s, subtracts k from all
+// remaining elements and returns a pointer to the updated set.
+// If k ≤ 0, the set is not changed.
+func (s *Set) Rsh(k int) *Set {
+	return s.ShiftDown(0, k)
+}
+
+// bitMask0 returns a bit mask with nonzero bits from 0 to n-1, 0 ≤ n < bpw.
+func bitMask0(n int) uint64 {
+	return 1<<uint(n) - 1
+}
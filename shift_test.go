@@ -0,0 +1,74 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestShift(t *testing.T) {
+	for _, s := range []*Set{
+		New(),
+		New(0),
+		New(1, 2, 3),
+		New(63, 64),
+		New(100, 200, 300),
+		New().AddRange(0, 200),
+		BuildTestSet(100),
+	} {
+		for _, from := range []int{-1, 0, 1, 2, 63, 64, 65, 150, 1000} {
+			for _, k := range []int{-1, 0, 1, 3, 63, 64, 65, 128, 200} {
+				up, down := new(Set), new(Set)
+				s.Visit(func(n int) (skip bool) {
+					switch {
+					case n < from:
+						up.Add(n)
+						down.Add(n)
+					case n < from+k:
+						up.Add(n + max(0, k))
+					default:
+						up.Add(n + max(0, k))
+						down.Add(n - max(0, k))
+					}
+					return
+				})
+				if res := new(Set).Set(s).ShiftUp(from, k); !res.Equal(up) {
+					t.Errorf("%v.ShiftUp(%d, %d) = %v; want %v", s, from, k, res, up)
+				} else {
+					CheckInvariants(t, "ShiftUp", res)
+				}
+				if res := new(Set).Set(s).ShiftDown(from, k); !res.Equal(down) {
+					t.Errorf("%v.ShiftDown(%d, %d) = %v; want %v", s, from, k, res, down)
+				} else {
+					CheckInvariants(t, "ShiftDown", res)
+				}
+			}
+		}
+	}
+}
+
+func TestLshRsh(t *testing.T) {
+	for _, x := range []struct {
+		s        *Set
+		k        int
+		lsh, rsh string
+	}{
+		{New(), 1, "{}", "{}"},
+		{New(0, 1, 5), 0, "{0 1 5}", "{0 1 5}"},
+		{New(0, 1, 5), 1, "{1 2 6}", "{0 4}"},
+		{New(0, 1, 5), 64, "{64 65 69}", "{}"},
+		{New(0, 1, 5), 1000, "{1000 1001 1005}", "{}"},
+		{New(10, 100), 10, "{20 110}", "{0 90}"},
+	} {
+		s := x.s
+		if res := new(Set).Set(s).Lsh(x.k); res.String() != x.lsh {
+			t.Errorf("%v.Lsh(%d) = %v; want %s", s, x.k, res, x.lsh)
+		}
+		if res := new(Set).Set(s).Rsh(x.k); res.String() != x.rsh {
+			t.Errorf("%v.Rsh(%d) = %v; want %s", s, x.k, res, x.rsh)
+		}
+	}
+
+	s := New(0, 1, 5)
+	if res := new(Set).Set(s).ShiftDown(1, MaxInt); res.String() != "{0}" {
+		t.Errorf("%v.ShiftDown(1, MaxInt) = %v; want {0}", s, res)
+	}
+}
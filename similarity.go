@@ -0,0 +1,75 @@
+package bit
+
+import (
+	"math"
+)
+
+// The similarity functions below compute all they need in a single pass
+// over the words of the two sets, without allocating memory.
+// Each returns 1 if both sets are empty.
+
+// Jaccard returns the Jaccard index |A ∩ B| / |A ∪ B| of a and b.
+func Jaccard(a, b *Set) float64 {
+	and, na, nb := sizes(a, b)
+	if or := na + nb - and; or > 0 {
+		return float64(and) / float64(or)
+	}
+	return 1
+}
+
+// Dice returns the Sørensen–Dice coefficient 2|A ∩ B| / (|A| + |B|) of a and b.
+func Dice(a, b *Set) float64 {
+	and, na, nb := sizes(a, b)
+	if na+nb > 0 {
+		return 2 * float64(and) / float64(na+nb)
+	}
+	return 1
+}
+
+// Cosine returns the cosine similarity |A ∩ B| / √(|A|·|B|) of a and b.
+func Cosine(a, b *Set) float64 {
+	and, na, nb := sizes(a, b)
+	switch {
+	case na == 0 && nb == 0:
+		return 1
+	case na == 0 || nb == 0:
+		return 0
+	}
+	return float64(and) / math.Sqrt(float64(na)*float64(nb))
+}
+
+// Overlap returns the overlap coefficient |A ∩ B| / min(|A|, |B|) of a and b.
+func Overlap(a, b *Set) float64 {
+	and, na, nb := sizes(a, b)
+	switch {
+	case na == 0 && nb == 0:
+		return 1
+	case na == 0 || nb == 0:
+		return 0
+	}
+	return float64(and) / float64(min(na, nb))
+}
+
+// Hamming returns the Hamming distance |A ∆ B| between a and b,
+// i.e. the number of elements that belong to either a or b, but not to both.
+func Hamming(a, b *Set) int {
+	return a.XorSize(b)
+}
+
+// sizes returns |A ∩ B|, |A| and |B|.
+func sizes(a, b *Set) (and, na, nb int) {
+	x, y := a.data, b.data
+	l := min(len(x), len(y))
+	for i := 0; i < l; i++ {
+		and += onesCount64(x[i] & y[i])
+		na += onesCount64(x[i])
+		nb += onesCount64(y[i])
+	}
+	for _, w := range x[l:] {
+		na += onesCount64(w)
+	}
+	for _, w := range y[l:] {
+		nb += onesCount64(w)
+	}
+	return
+}
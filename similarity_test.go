@@ -0,0 +1,45 @@
+package bit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	for _, x := range []struct {
+		a, b                           *Set
+		jaccard, dice, cosine, overlap float64
+		hamming                        int
+	}{
+		{New(), New(), 1, 1, 1, 1, 0},
+		{New(1), New(), 0, 0, 0, 0, 1},
+		{New(), New(100), 0, 0, 0, 0, 1},
+		{New(1, 2), New(1, 2), 1, 1, 1, 1, 0},
+		{New(1, 2), New(3, 4), 0, 0, 0, 0, 4},
+		{New(1, 2), New(2, 3), 1.0 / 3, 0.5, 0.5, 0.5, 2},
+		{New(1, 2, 3, 4), New(4), 0.25, 0.4, 0.5, 1, 3},
+		{New(1, 200), New(1, 100, 200, 300), 0.5, 2.0 / 3, 1 / math.Sqrt(2), 1, 2},
+	} {
+		a, b := x.a, x.b
+		for _, y := range []struct {
+			res, exp float64
+			name     string
+		}{
+			{Jaccard(a, b), x.jaccard, "Jaccard"},
+			{Dice(a, b), x.dice, "Dice"},
+			{Cosine(a, b), x.cosine, "Cosine"},
+			{Overlap(a, b), x.overlap, "Overlap"},
+			{Jaccard(b, a), x.jaccard, "Jaccard"},
+			{Dice(b, a), x.dice, "Dice"},
+			{Cosine(b, a), x.cosine, "Cosine"},
+			{Overlap(b, a), x.overlap, "Overlap"},
+		} {
+			if math.Abs(y.res-y.exp) > 1e-12 {
+				t.Errorf("%s(%v, %v) = %v; want %v", y.name, a, b, y.res, y.exp)
+			}
+		}
+		if h := Hamming(a, b); h != x.hamming {
+			t.Errorf("Hamming(%v, %v) = %d; want %d", a, b, h, x.hamming)
+		}
+	}
+}
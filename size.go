@@ -0,0 +1,75 @@
+package bit
+
+// AndSize returns the size of the intersection s1 ∩ s2.
+// It does not allocate memory.
+func (s1 *Set) AndSize(s2 *Set) int {
+	a, b := s1.data, s2.data
+	n := 0
+	for i, l := 0, min(len(a), len(b)); i < l; i++ {
+		n += onesCount64(a[i] & b[i])
+	}
+	return n
+}
+
+// OrSize returns the size of the union s1 ∪ s2.
+// It does not allocate memory.
+func (s1 *Set) OrSize(s2 *Set) int {
+	// Swap, if necessary, to make s1 shorter than s2.
+	if len(s1.data) > len(s2.data) {
+		s1, s2 = s2, s1
+	}
+	a, b := s1.data, s2.data
+	n := 0
+	for i := range a {
+		n += onesCount64(a[i] | b[i])
+	}
+	for _, w := range b[len(a):] {
+		n += onesCount64(w)
+	}
+	return n
+}
+
+// XorSize returns the size of the symmetric difference s1 ∆ s2.
+// It does not allocate memory.
+func (s1 *Set) XorSize(s2 *Set) int {
+	// Swap, if necessary, to make s1 shorter than s2.
+	if len(s1.data) > len(s2.data) {
+		s1, s2 = s2, s1
+	}
+	a, b := s1.data, s2.data
+	n := 0
+	for i := range a {
+		n += onesCount64(a[i] ^ b[i])
+	}
+	for _, w := range b[len(a):] {
+		n += onesCount64(w)
+	}
+	return n
+}
+
+// AndNotSize returns the size of the set difference s1 ∖ s2.
+// It does not allocate memory.
+func (s1 *Set) AndNotSize(s2 *Set) int {
+	a, b := s1.data, s2.data
+	n := 0
+	l := min(len(a), len(b))
+	for i := 0; i < l; i++ {
+		n += onesCount64(a[i] &^ b[i])
+	}
+	for _, w := range a[l:] {
+		n += onesCount64(w)
+	}
+	return n
+}
+
+// Intersects tells if s1 and s2 have at least one element in common.
+// It stops at the first common word.
+func (s1 *Set) Intersects(s2 *Set) bool {
+	a, b := s1.data, s2.data
+	for i, l := 0, min(len(a), len(b)); i < l; i++ {
+		if a[i]&b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package bit
+
+import (
+	"testing"
+)
+
+func TestBinOpSize(t *testing.T) {
+	sets := []*Set{
+		New(),
+		New(1),
+		New(1, 2),
+		New(2, 3),
+		New(100),
+		New(100, 200),
+		New(200, 300),
+		New().AddRange(0, 1000),
+		BuildTestSet(100),
+	}
+	for _, a := range sets {
+		for _, b := range sets {
+			for _, x := range []struct {
+				size int
+				exp  int
+				name string
+			}{
+				{a.AndSize(b), a.And(b).Size(), "AndSize"},
+				{a.OrSize(b), a.Or(b).Size(), "OrSize"},
+				{a.XorSize(b), a.Xor(b).Size(), "XorSize"},
+				{a.AndNotSize(b), a.AndNot(b).Size(), "AndNotSize"},
+			} {
+				if x.size != x.exp {
+					t.Errorf("%v.%s(%v) = %d; want %d", a, x.name, b, x.size, x.exp)
+				}
+			}
+			if res, exp := a.Intersects(b), !a.And(b).Empty(); res != exp {
+				t.Errorf("%v.Intersects(%v) = %t; want %t", a, b, res, exp)
+			}
+		}
+	}
+}
+
+func TestBinOpSizeAllocs(t *testing.T) {
+	a, b := BuildTestSet(1000), New().AddRange(100, 10000)
+	allocs := testing.AllocsPerRun(10, func() {
+		a.AndSize(b)
+		a.OrSize(b)
+		a.XorSize(b)
+		a.AndNotSize(b)
+		a.Intersects(b)
+	})
+	if allocs != 0 {
+		t.Errorf("binary operation sizes allocated %v times; want 0", allocs)
+	}
+}
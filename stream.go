@@ -0,0 +1,134 @@
+package bit
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+)
+
+// The stream encoding used by WriteTo and ReadFrom consists of
+//   - a header: the magic string "bit", a version byte, and the number
+//     of 64-bit words as an 8-byte little-endian integer,
+//   - the words in little-endian order,
+//   - a CRC-32 (Castagnoli) checksum of the header and the words,
+//     as a 4-byte little-endian integer.
+const (
+	streamMagic   = "bit"
+	streamVersion = 1
+	headerSize    = len(streamMagic) + 1 + 8
+	chunkWords    = 512     // words per chunk, chosen to fit in L1 cache
+	maxPrealloc   = 1 << 20 // maximum number of words allocated up front
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// WriteTo implements the io.WriterTo interface. It writes s to w in
+// chunks, without first encoding the whole set in memory. The return
+// value n is the number of bytes written.
+func (s *Set) WriteTo(w io.Writer) (n int64, err error) {
+	d := s.data
+	crc := crc32.New(crcTable)
+	mw := io.MultiWriter(w, crc)
+
+	var head [headerSize]byte
+	copy(head[:], streamMagic)
+	head[len(streamMagic)] = streamVersion
+	binary.LittleEndian.PutUint64(head[len(streamMagic)+1:], uint64(len(d)))
+	k, err := mw.Write(head[:])
+	n += int64(k)
+	if err != nil {
+		return
+	}
+
+	buf := make([]byte, 8*min(len(d), chunkWords))
+	for len(d) > 0 {
+		c := min(len(d), chunkWords)
+		for i, x := range d[:c] {
+			binary.LittleEndian.PutUint64(buf[8*i:], x)
+		}
+		k, err = mw.Write(buf[:8*c])
+		n += int64(k)
+		if err != nil {
+			return
+		}
+		d = d[c:]
+	}
+
+	var sum [4]byte
+	binary.LittleEndian.PutUint32(sum[:], crc.Sum32())
+	k, err = w.Write(sum[:])
+	n += int64(k)
+	return
+}
+
+// ReadFrom implements the io.ReaderFrom interface. It sets s to a set
+// read from r, as written by WriteTo. ReadFrom reads exactly the bytes
+// of one encoded set, reading the words in chunks. The return value n
+// is the number of bytes read.
+//
+// If the data is truncated, ReadFrom returns io.ErrUnexpectedEOF;
+// if it is corrupt, ReadFrom returns a *FormatError.
+// In both cases s is left unchanged.
+func (s *Set) ReadFrom(r io.Reader) (n int64, err error) {
+	crc := crc32.New(crcTable)
+	tr := io.TeeReader(r, crc)
+
+	var head [headerSize]byte
+	k, err := io.ReadFull(tr, head[:])
+	n += int64(k)
+	if err != nil {
+		return n, unexpectedEOF(err)
+	}
+	if string(head[:len(streamMagic)]) != streamMagic {
+		return n, streamError("bad magic")
+	}
+	if head[len(streamMagic)] != streamVersion {
+		return n, streamError("unknown version")
+	}
+	count := binary.LittleEndian.Uint64(head[len(streamMagic)+1:])
+	if count > uint64(MaxInt>>shift) {
+		return n, streamError("word count out of range")
+	}
+
+	d := make([]uint64, 0, min(int(count), maxPrealloc))
+	buf := make([]byte, 8*min(int(count), chunkWords))
+	for left := int(count); left > 0; {
+		c := min(left, chunkWords)
+		k, err = io.ReadFull(tr, buf[:8*c])
+		n += int64(k)
+		if err != nil {
+			return n, unexpectedEOF(err)
+		}
+		for i := 0; i < c; i++ {
+			d = append(d, binary.LittleEndian.Uint64(buf[8*i:]))
+		}
+		left -= c
+	}
+
+	var sum [4]byte
+	k, err = io.ReadFull(r, sum[:])
+	n += int64(k)
+	if err != nil {
+		return n, unexpectedEOF(err)
+	}
+	if binary.LittleEndian.Uint32(sum[:]) != crc.Sum32() {
+		return n, streamError("checksum mismatch")
+	}
+	if len(d) > 0 && d[len(d)-1] == 0 {
+		return n, streamError("trailing zero word")
+	}
+	s.data = d
+	return n, nil
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func streamError(msg string) error {
+	return &FormatError{Format: "stream", Msg: msg}
+}
@@ -0,0 +1,69 @@
+package bit
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	var buf bytes.Buffer
+	sets := []*Set{
+		New(),
+		New(0),
+		New(1, 2, 3),
+		New(100, 200, 300),
+		New().AddRange(10, 100000).Add(200000),
+	}
+	for _, s := range sets {
+		n, err := s.WriteTo(&buf)
+		if err != nil || n != int64(headerSize+8*len(s.data)+4) {
+			t.Errorf("%v.WriteTo() = %d, %v; want %d, nil", s, n, err, headerSize+8*len(s.data)+4)
+		}
+	}
+	// Sets written one after the other should be read back one at a time.
+	for _, s := range sets {
+		res := New(5, 500)
+		n, err := res.ReadFrom(&buf)
+		if err != nil || n != int64(headerSize+8*len(s.data)+4) {
+			t.Errorf("ReadFrom() = %d, %v; want %d, nil", n, err, headerSize+8*len(s.data)+4)
+		}
+		if !res.Equal(s) {
+			t.Errorf("ReadFrom() read %v; want %v", res, s)
+		}
+		CheckInvariants(t, "ReadFrom", res)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("ReadFrom() left %d unread bytes", buf.Len())
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	var buf bytes.Buffer
+	New(1, 2, 100).WriteTo(&buf)
+	data := buf.Bytes()
+
+	for i := 0; i < len(data); i++ {
+		s := New(1, 2, 3)
+		_, err := s.ReadFrom(bytes.NewReader(data[:i]))
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("ReadFrom(%v) returned error %v; want %v", data[:i], err, io.ErrUnexpectedEOF)
+		}
+		if !s.Equal(New(1, 2, 3)) {
+			t.Errorf("ReadFrom(%v) changed set to %v", data[:i], s)
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		corrupt := append([]byte(nil), data...)
+		corrupt[i] ^= 0x01
+		s := New(1, 2, 3)
+		_, err := s.ReadFrom(bytes.NewReader(corrupt))
+		if err == nil {
+			t.Errorf("ReadFrom(%v) returned nil error", corrupt)
+		}
+		if !s.Equal(New(1, 2, 3)) {
+			t.Errorf("ReadFrom(%v) changed set to %v", corrupt, s)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build go1.23
+// +build go1.23
+
+package typed
+
+import (
+	"iter"
+)
+
+// All returns an iterator over the elements of s in numerical order.
+// The same rules as for Visit apply to changes made to s during iteration.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.Visit(func(n T) (skip bool) {
+			return !yield(n)
+		})
+	}
+}
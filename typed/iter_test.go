@@ -0,0 +1,25 @@
+//go:build go1.23
+// +build go1.23
+
+package typed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIter(t *testing.T) {
+	s := New[UserID](5, 1, 300)
+	var all []UserID
+	for n := range s.All() {
+		all = append(all, n)
+	}
+	if exp := []UserID{1, 5, 300}; !reflect.DeepEqual(all, exp) {
+		t.Errorf("%v.All() yields %v; want %v", s, all, exp)
+	}
+	for n := range s.All() {
+		if n == 5 {
+			break
+		}
+	}
+}
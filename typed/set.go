@@ -0,0 +1,184 @@
+//go:build go1.18
+// +build go1.18
+
+// Package typed provides a bit set whose elements have an integer type
+// other than int, such as
+//
+//	type UserID int32
+//	type Port uint16
+//
+// The Set type in this package is a thin wrapper around bit.Set
+// that saves the conversions to and from int at every call site and
+// lets the compiler check that sets of different types aren't mixed.
+package typed
+
+import (
+	"github.com/yourbasic/bit"
+)
+
+// Integer is the set of types that can be used as set elements.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Set represents a mutable set of non-negative integers of type T.
+// The zero value is an empty set ready to use.
+// A set occupies approximately n bits, where n is the maximum value
+// that has been stored in the set.
+type Set[T Integer] struct {
+	bits bit.Set
+}
+
+// New creates a new set with the given elements.
+// Negative numbers, and numbers larger than bit.MaxInt,
+// are not included in the set.
+func New[T Integer](n ...T) *Set[T] {
+	s := new(Set[T])
+	for _, e := range n {
+		s.Add(e)
+	}
+	return s
+}
+
+// toInt converts n to an int; ok is false if n isn't a non-negative int.
+func toInt[T Integer](n T) (x int, ok bool) {
+	x = int(n)
+	return x, x >= 0 && T(x) == n
+}
+
+// Bits returns the underlying bit set of s.
+// Changes to the returned set are visible in s, and vice versa.
+func (s *Set[T]) Bits() *bit.Set {
+	return &s.bits
+}
+
+// Contains tells if n is an element of the set.
+func (s *Set[T]) Contains(n T) bool {
+	x, ok := toInt(n)
+	return ok && s.bits.Contains(x)
+}
+
+// Equal tells if s1 and s2 contain the same elements.
+func (s1 *Set[T]) Equal(s2 *Set[T]) bool {
+	return s1.bits.Equal(&s2.bits)
+}
+
+// Subset tells if s1 is a subset of s2.
+func (s1 *Set[T]) Subset(s2 *Set[T]) bool {
+	return s1.bits.Subset(&s2.bits)
+}
+
+// Max returns the maximum element of the set;
+// it panics if the set is empty.
+func (s *Set[T]) Max() T {
+	return T(s.bits.Max())
+}
+
+// Min returns the minimum element of the set;
+// it panics if the set is empty.
+func (s *Set[T]) Min() T {
+	return T(s.bits.Min())
+}
+
+// Size returns the number of elements in the set.
+// This method scans the set; to check if a set is empty,
+// consider using the more efficient Empty method.
+func (s *Set[T]) Size() int {
+	return s.bits.Size()
+}
+
+// Empty tells if the set is empty.
+func (s *Set[T]) Empty() bool {
+	return s.bits.Empty()
+}
+
+// Visit calls the do function for each element of s in numerical order.
+// If do returns true, Visit returns immediately, skipping any remaining
+// elements, and returns true. It is safe for do to add or delete
+// elements e, e ≤ n. The behavior of Visit is undefined if do changes
+// the set in any other way.
+func (s *Set[T]) Visit(do func(n T) (skip bool)) (aborted bool) {
+	return s.bits.Visit(func(n int) (skip bool) {
+		return do(T(n))
+	})
+}
+
+// String returns a string representation of the set. The elements
+// are listed in ascending order. Runs of at least three consecutive
+// elements from a to b are given as a..b.
+func (s *Set[T]) String() string {
+	return s.bits.String()
+}
+
+// Add adds n to s and returns a pointer to the updated set.
+// A negative n, or an n larger than bit.MaxInt, will not be added.
+func (s *Set[T]) Add(n T) *Set[T] {
+	if x, ok := toInt(n); ok {
+		s.bits.Add(x)
+	}
+	return s
+}
+
+// Delete removes n from s and returns a pointer to the updated set.
+func (s *Set[T]) Delete(n T) *Set[T] {
+	if x, ok := toInt(n); ok {
+		s.bits.Delete(x)
+	}
+	return s
+}
+
+// And creates a new set that consists of all elements that belong
+// to both s1 and s2.
+func (s1 *Set[T]) And(s2 *Set[T]) *Set[T] {
+	return new(Set[T]).SetAnd(s1, s2)
+}
+
+// Or creates a new set that contains all elements that belong
+// to either s1 or s2.
+func (s1 *Set[T]) Or(s2 *Set[T]) *Set[T] {
+	return new(Set[T]).SetOr(s1, s2)
+}
+
+// Xor creates a new set that contains all elements that belong
+// to either s1 or s2, but not to both.
+func (s1 *Set[T]) Xor(s2 *Set[T]) *Set[T] {
+	return new(Set[T]).SetXor(s1, s2)
+}
+
+// AndNot creates a new set that consists of all elements that belong
+// to s1, but not to s2.
+func (s1 *Set[T]) AndNot(s2 *Set[T]) *Set[T] {
+	return new(Set[T]).SetAndNot(s1, s2)
+}
+
+// Set sets s to s1 and then returns a pointer to the updated set s.
+func (s *Set[T]) Set(s1 *Set[T]) *Set[T] {
+	s.bits.Set(&s1.bits)
+	return s
+}
+
+// SetAnd sets s to the intersection s1 ∩ s2 and then returns a pointer to s.
+func (s *Set[T]) SetAnd(s1, s2 *Set[T]) *Set[T] {
+	s.bits.SetAnd(&s1.bits, &s2.bits)
+	return s
+}
+
+// SetAndNot sets s to the set difference s1 ∖ s2 and then returns a pointer to s.
+func (s *Set[T]) SetAndNot(s1, s2 *Set[T]) *Set[T] {
+	s.bits.SetAndNot(&s1.bits, &s2.bits)
+	return s
+}
+
+// SetOr sets s to the union s1 ∪ s2 and then returns a pointer to s.
+func (s *Set[T]) SetOr(s1, s2 *Set[T]) *Set[T] {
+	s.bits.SetOr(&s1.bits, &s2.bits)
+	return s
+}
+
+// SetXor sets s to the symmetric difference A ∆ B = (A ∪ B) ∖ (A ∩ B)
+// and then returns a pointer to s.
+func (s *Set[T]) SetXor(s1, s2 *Set[T]) *Set[T] {
+	s.bits.SetXor(&s1.bits, &s2.bits)
+	return s
+}
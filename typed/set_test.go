@@ -0,0 +1,100 @@
+//go:build go1.18
+// +build go1.18
+
+package typed
+
+import (
+	"testing"
+
+	"github.com/yourbasic/bit"
+)
+
+type UserID int32
+
+type Port uint16
+
+func TestSet(t *testing.T) {
+	s := New[UserID](3, 1, -5, 2, 100)
+	if str := s.String(); str != "{1..3 100}" {
+		t.Errorf("s.String() = %q; want %q", str, "{1..3 100}")
+	}
+	if size := s.Size(); size != 4 {
+		t.Errorf("%v.Size() = %d; want 4", s, size)
+	}
+	if min, max := s.Min(), s.Max(); min != 1 || max != 100 {
+		t.Errorf("%v.Min(), Max() = %d, %d; want 1, 100", s, min, max)
+	}
+	for _, x := range []struct {
+		n        UserID
+		contains bool
+	}{
+		{-5, false},
+		{0, false},
+		{1, true},
+		{100, true},
+		{101, false},
+	} {
+		if s.Contains(x.n) != x.contains {
+			t.Errorf("%v.Contains(%d) = %t; want %t", s, x.n, !x.contains, x.contains)
+		}
+	}
+	s.Delete(2).Delete(-5).Add(UserID(7))
+	if str := s.String(); str != "{1 3 7 100}" {
+		t.Errorf("s.String() = %q; want %q", str, "{1 3 7 100}")
+	}
+	if !s.Bits().Equal(bit.New(1, 3, 7, 100)) {
+		t.Errorf("%v.Bits() = %v; want {1 3 7 100}", s, s.Bits())
+	}
+
+	// Values that don't fit in a non-negative int.
+	u := New[uint64](1<<64-1, 1)
+	if str := u.String(); str != "{1}" {
+		t.Errorf("u.String() = %q; want %q", str, "{1}")
+	}
+	if u.Contains(1<<64 - 1) {
+		t.Errorf("%v.Contains(%d) = true; want false", u, uint64(1<<64-1))
+	}
+
+	// Visit
+	var ports []Port
+	p := New[Port](80, 443, 65535)
+	p.Visit(func(n Port) (skip bool) {
+		ports = append(ports, n)
+		return n == 443
+	})
+	if len(ports) != 2 || ports[0] != 80 || ports[1] != 443 {
+		t.Errorf("%v.Visit visits %v; want [80 443]", p, ports)
+	}
+	if max := p.Max(); max != 65535 {
+		t.Errorf("%v.Max() = %d; want 65535", p, max)
+	}
+	if !New[Port]().Empty() || p.Empty() {
+		t.Errorf("Empty() gives wrong result")
+	}
+}
+
+func TestSetBinOp(t *testing.T) {
+	a := New[Port](1, 2, 3, 1000)
+	b := New[Port](2, 3, 4, 2000)
+	for _, x := range []struct {
+		res *Set[Port]
+		exp string
+		op  string
+	}{
+		{a.And(b), "{2 3}", "And"},
+		{a.Or(b), "{1..4 1000 2000}", "Or"},
+		{a.Xor(b), "{1 4 1000 2000}", "Xor"},
+		{a.AndNot(b), "{1 1000}", "AndNot"},
+		{new(Set[Port]).Set(a), "{1..3 1000}", "Set"},
+	} {
+		if str := x.res.String(); str != x.exp {
+			t.Errorf("%v.%s(%v) = %s; want %s", a, x.op, b, str, x.exp)
+		}
+	}
+	if !a.And(b).Subset(a) || a.Subset(b) {
+		t.Errorf("Subset gives wrong result")
+	}
+	if !a.Equal(New[Port](1000, 3, 2, 1)) || a.Equal(b) {
+		t.Errorf("Equal gives wrong result")
+	}
+}
@@ -0,0 +1,18 @@
+// +build !go1.9
+
+package bit
+
+// Word functions used by the parts of the package that are not
+// specific to a Go version. In Go 1.9 and later, they are implemented
+// using package math/bits.
+
+// onesCount64 returns the number of nonzero bits in w.
+func onesCount64(w uint64) int { return Count(w) }
+
+// trailingZeros64 returns the number of trailing zero bits in w;
+// it returns 64 when w is zero.
+func trailingZeros64(w uint64) int { return TrailingZeros(w) }
+
+// len64 returns the minimum number of bits required to represent w;
+// it returns 0 when w is zero.
+func len64(w uint64) int { return 64 - LeadingZeros(w) }
@@ -0,0 +1,21 @@
+// +build go1.9
+
+package bit
+
+import (
+	"math/bits"
+)
+
+// Word functions used by the parts of the package that are not
+// specific to a Go version.
+
+// onesCount64 returns the number of nonzero bits in w.
+func onesCount64(w uint64) int { return bits.OnesCount64(w) }
+
+// trailingZeros64 returns the number of trailing zero bits in w;
+// it returns 64 when w is zero.
+func trailingZeros64(w uint64) int { return bits.TrailingZeros64(w) }
+
+// len64 returns the minimum number of bits required to represent w;
+// it returns 0 when w is zero.
+func len64(w uint64) int { return bits.Len64(w) }
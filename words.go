@@ -0,0 +1,28 @@
+package bit
+
+// The words of a set s hold its elements in increasing order:
+// bit j of word i is set if i*64 + j belongs to s.
+// The last word is nonzero, and the empty set has no words.
+
+// Words returns the words of s. The returned slice shares memory with s;
+// the caller must not modify it, and it's only valid until s is changed.
+// Appending to the slice doesn't affect s.
+func (s *Set) Words() []uint64 {
+	return s.data[:len(s.data):len(s.data)]
+}
+
+// AppendWords appends a copy of the words of s to buf
+// and returns the extended buffer.
+func (s *Set) AppendWords(buf []uint64) []uint64 {
+	return append(buf, s.data...)
+}
+
+// FromWords creates a new set whose elements are given by the words in w.
+// The set takes ownership of w: it may change the words in w, and the
+// caller must not modify w after the call. Trailing zero words are
+// removed and are never accessed by the set.
+func FromWords(w []uint64) *Set {
+	s := &Set{data: w[:len(w):len(w)]}
+	s.trim()
+	return s
+}
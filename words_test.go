@@ -0,0 +1,56 @@
+package bit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	for _, x := range []struct {
+		s     *Set
+		words []uint64
+	}{
+		{New(), nil},
+		{New(0), []uint64{1}},
+		{New(1, 64, 129), []uint64{2, 1, 2}},
+		{new(Set).AddRange(0, 64), []uint64{maxw}},
+		{New(300).Delete(300).Add(1), []uint64{2}},
+	} {
+		s := x.s
+		if w := s.Words(); !reflect.DeepEqual(w, x.words) {
+			t.Errorf("%v.Words() = %v; want %v", s, w, x.words)
+		}
+		if w := s.AppendWords([]uint64{7}); !reflect.DeepEqual(w, append([]uint64{7}, x.words...)) {
+			t.Errorf("%v.AppendWords([7]) = %v; want %v", s, w, append([]uint64{7}, x.words...))
+		}
+		if res := FromWords(s.AppendWords(nil)); !res.Equal(s) {
+			t.Errorf("FromWords(%v) = %v; want %v", x.words, res, s)
+		}
+	}
+
+	// Appending to the result of Words doesn't change the set.
+	s := New(1, 2, 3)
+	w := s.Words()
+	_ = append(w, 5)
+	CheckInvariants(t, "Words", s)
+
+	// FromWords adopts the slice and trims trailing zero words,
+	// ignoring anything beyond the end of the slice.
+	buf := []uint64{0, 6, 0, 0, 1}
+	s = FromWords(buf[:4])
+	if str := s.String(); str != "{65 66}" {
+		t.Errorf("FromWords(%v) = %s; want {65 66}", buf[:4], str)
+	}
+	CheckInvariants(t, "FromWords", s)
+	s.Add(64)
+	if buf[1] != 7 {
+		t.Errorf("FromWords copies the slice")
+	}
+	if s.Add(200); buf[4] != 1 {
+		t.Errorf("FromWords writes beyond the end of the slice")
+	}
+	if s := FromWords([]uint64{0, 0}); !s.Empty() {
+		t.Errorf("FromWords([0 0]) = %v; want {}", s)
+	}
+	CheckInvariants(t, "FromWords", s)
+}